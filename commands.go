@@ -0,0 +1,382 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	goparser "go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reddit-go-api-generator/docs"
+	"reddit-go-api-generator/models"
+	"reddit-go-api-generator/openapi"
+	"reddit-go-api-generator/parser"
+	"reddit-go-api-generator/report"
+	"reddit-go-api-generator/scraper"
+	"reddit-go-api-generator/writer"
+	"sort"
+	"strings"
+)
+
+func runScrape(args []string) error {
+	var out, untypedOut string
+	cfg, err := parseFlags("scrape", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&out, "out", "endpoints.json", "File to write the scraped endpoints to, or - for stdout")
+		fs.StringVar(&untypedOut, "untyped", "", "File to write the fields no type rule matched to, or - for stdout")
+	})
+	if err != nil {
+		return err
+	}
+	cfg.Input = ""
+
+	warnings := &report.Collector{}
+	endpoints, err := loadEndpoints(cfg, warnings)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(endpoints, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode endpoints: %w", err)
+	}
+
+	if err := writeOutput(out, append(content, '\n')); err != nil {
+		return err
+	}
+
+	slog.Info("Wrote endpoints", "count", len(endpoints), "file", out)
+
+	untyped := scraper.UntypedFields(endpoints)
+	slog.Info("Fields without an inferred type", "count", len(untyped))
+	if untypedOut != "" {
+		if untyped == nil {
+			untyped = []scraper.UntypedField{}
+		}
+		content, err := json.MarshalIndent(untyped, "", "  ")
+		if err != nil {
+			return fmt.Errorf("could not encode untyped fields: %w", err)
+		}
+		if err := writeOutput(untypedOut, append(content, '\n')); err != nil {
+			return err
+		}
+	}
+	return reportWarnings(cfg, endpoints, warnings)
+}
+
+func runGenerate(args []string) error {
+	cfg, err := parseFlags("generate", args, nil)
+	if err != nil {
+		return err
+	}
+
+	warnings := &report.Collector{}
+	endpoints, err := loadEndpoints(cfg, warnings)
+	if err != nil {
+		return err
+	}
+
+	// A conflicting module is reported before anything is written
+	if _, _, err := resolveModule(cfg.Output, cfg.Module, cfg.InParentModule); err != nil {
+		return fmt.Errorf("error initializing Go module: %w", err)
+	}
+
+	result, err := writer.Write(cfg.Output, sdkFiles(cfg, endpoints), writer.Options{Clean: cfg.Clean, Header: parser.GeneratedHeader})
+	if err != nil {
+		return fmt.Errorf("error writing SDK: %w", err)
+	}
+
+	for _, written := range result.Written {
+		slog.Debug("Wrote generated file", "file", written)
+	}
+	for _, removed := range result.Removed {
+		slog.Info("Removed stale generated file", "file", removed)
+	}
+
+	// Initialize the Go module after writing the file, unless the SDK already lives in one
+	if err := ensureGoModule(cfg.Output, cfg.Module, cfg.GoVersion, cfg.InParentModule); err != nil {
+		return fmt.Errorf("error initializing Go module: %w", err)
+	}
+
+	slog.Info("Successfully built ReddiGo SDK", "endpoints", len(endpoints), "written", len(result.Written), "unchanged", len(result.Unchanged))
+	return reportWarnings(cfg, endpoints, warnings)
+}
+
+func runDiff(args []string) error {
+	cfg, err := parseFlags("diff", args, nil)
+	if err != nil {
+		return err
+	}
+
+	endpoints, err := loadEndpoints(cfg, nil)
+	if err != nil {
+		return err
+	}
+
+	files := sdkFiles(cfg, endpoints)
+	changes := 0
+
+	for _, file := range files {
+		target := filepath.Join(cfg.Output, file.Name)
+
+		existing, err := os.ReadFile(target)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			fmt.Printf("+ %s (new file)\n", target)
+			changes++
+		case err != nil:
+			return err
+		case !bytes.Equal(existing, file.Content):
+			fmt.Printf("~ %s (first difference at line %d)\n", target, firstDifferentLine(existing, file.Content))
+			changes++
+		}
+	}
+
+	stale, err := writer.StaleFiles(cfg.Output, files, parser.GeneratedHeader)
+	if err != nil {
+		return err
+	}
+	for _, path := range stale {
+		fmt.Printf("- %s (no longer generated, removed with -clean)\n", path)
+		changes++
+	}
+
+	if changes > 0 {
+		return withExitCode(exitDiff, fmt.Errorf("%d generated files differ", changes))
+	}
+
+	slog.Info("Generated SDK is up to date", "dir", cfg.Output)
+	return nil
+}
+
+func runValidate(args []string) error {
+	cfg, err := parseFlags("validate", args, nil)
+	if err != nil {
+		return err
+	}
+
+	warnings := &report.Collector{}
+	endpoints, err := loadEndpoints(cfg, warnings)
+	if err != nil {
+		return err
+	}
+
+	problems := typeCheck(sdkFiles(cfg, endpoints))
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+
+	if len(problems) > 0 {
+		return withExitCode(exitInvalid, fmt.Errorf("generated SDK has %d problems", len(problems)))
+	}
+
+	slog.Info("Generated SDK type-checks", "endpoints", len(endpoints))
+	return reportWarnings(cfg, endpoints, warnings)
+}
+
+func runDocs(args []string) error {
+	var out, format string
+	cfg, err := parseFlags("docs", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&out, "out", "docs", "Directory to write the reference pages to")
+		fs.StringVar(&format, "format", docs.FormatMarkdown, "Output format, markdown or html")
+	})
+	if err != nil {
+		return err
+	}
+
+	endpoints, err := loadEndpoints(cfg, nil)
+	if err != nil {
+		return err
+	}
+
+	files, err := docs.Generate(endpoints, docs.Options{PackageName: cfg.Package, Format: format})
+	if err != nil {
+		return withExitCode(exitUsage, err)
+	}
+
+	result, err := writer.Write(out, files, writer.Options{Header: docs.Header})
+	if err != nil {
+		return fmt.Errorf("error writing docs: %w", err)
+	}
+
+	slog.Info("Wrote reference docs", "dir", out, "pages", len(files), "written", len(result.Written))
+	return nil
+}
+
+func runOpenAPI(args []string) error {
+	var out string
+	cfg, err := parseFlags("openapi", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&out, "out", "openapi.json", "File to write the OpenAPI document to, or - for stdout")
+	})
+	if err != nil {
+		return err
+	}
+
+	endpoints, err := loadEndpoints(cfg, nil)
+	if err != nil {
+		return err
+	}
+
+	content, err := openapi.Export(endpoints).Marshal()
+	if err != nil {
+		return err
+	}
+
+	if err := writeOutput(out, content); err != nil {
+		return err
+	}
+
+	slog.Info("Wrote OpenAPI document", "endpoints", len(endpoints), "file", out)
+	return nil
+}
+
+// loadEndpoints reads endpoints from cfg.Input, or scrapes the Reddit documentation when it is
+// empty. The problems found with the endpoints are recorded in warnings, which may be nil.
+func loadEndpoints(cfg Config, warnings *report.Collector) ([]models.Endpoint, error) {
+	if cfg.Input != "" {
+		content, err := os.ReadFile(cfg.Input)
+		if err != nil {
+			return nil, fmt.Errorf("could not read endpoints: %w", err)
+		}
+
+		var endpoints []models.Endpoint
+		if err := json.Unmarshal(content, &endpoints); err != nil {
+			return nil, fmt.Errorf("could not decode endpoints from %s: %w", cfg.Input, err)
+		}
+
+		// The extraction warnings are lost with the page, the endpoints can still be checked
+		for _, endpoint := range endpoints {
+			warnings.Add(scraper.EndpointWarnings(endpoint)...)
+		}
+
+		slog.Info("Loaded endpoints", "count", len(endpoints), "file", cfg.Input)
+		return endpoints, nil
+	}
+
+	endpoints, err := scraper.Scrape(scraper.Options{
+		URL:                 cfg.URL,
+		Limit:               cfg.Limit,
+		OnEndpointTargeted:  func(id string) { slog.Debug("Targeted endpoint", "id", id) },
+		OnEndpointProcessed: func(id string) { slog.Debug("Processed endpoint", "id", id) },
+		OnProgress:          newProgressReporter(cfg),
+		Warnings:            warnings,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error scraping the Reddit API: %w", err)
+	}
+
+	slog.Info("Successfully scraped endpoints", "count", len(endpoints))
+	return endpoints, nil
+}
+
+// reportWarnings adds the naming warnings of endpoints to warnings, writes them to cfg.Report
+// and fails when there are more than cfg.MaxWarnings
+func reportWarnings(cfg Config, endpoints []models.Endpoint, warnings *report.Collector) error {
+	warnings.Add(parser.NamingWarnings(endpoints)...)
+	all := warnings.Warnings()
+
+	counts := report.Counts(all)
+	codes := make([]string, 0, len(counts))
+	for code := range counts {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	attrs := []any{"count", len(all)}
+	for _, code := range codes {
+		attrs = append(attrs, code, counts[code])
+	}
+	slog.Info("Quality warnings", attrs...)
+
+	if cfg.Report != "" {
+		content, err := report.JSON(all)
+		if err != nil {
+			return fmt.Errorf("could not encode quality report: %w", err)
+		}
+		if strings.EqualFold(filepath.Ext(cfg.Report), ".md") {
+			content = report.Markdown(all)
+		}
+		if err := writeOutput(cfg.Report, content); err != nil {
+			return err
+		}
+		slog.Info("Wrote quality report", "warnings", len(all), "file", cfg.Report)
+	}
+
+	if cfg.MaxWarnings >= 0 && len(all) > cfg.MaxWarnings {
+		return withExitCode(exitWarnings, fmt.Errorf("%d quality warnings, more than the %d allowed", len(all), cfg.MaxWarnings))
+	}
+	return nil
+}
+
+// sdkFiles renders the SDK for endpoints into the files that make up the output directory
+func sdkFiles(cfg Config, endpoints []models.Endpoint) []writer.File {
+	return parser.GenerateSDK(endpoints, parser.Options{PackageName: cfg.Package})
+}
+
+// typeCheck parses and type-checks the generated files, grouped by directory
+func typeCheck(files []writer.File) []string {
+	var problems []string
+
+	fset := token.NewFileSet()
+	packages := make(map[string][]*ast.File)
+	for _, file := range files {
+		if filepath.Ext(file.Name) != ".go" {
+			continue
+		}
+		parsed, err := goparser.ParseFile(fset, file.Name, file.Content, goparser.AllErrors)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		dir := filepath.Dir(file.Name)
+		packages[dir] = append(packages[dir], parsed)
+	}
+
+	dirs := make([]string, 0, len(packages))
+	for dir := range packages {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	for _, dir := range dirs {
+		conf := types.Config{
+			Importer: importer.Default(),
+			Error: func(err error) {
+				problems = append(problems, err.Error())
+			},
+		}
+		_, _ = conf.Check(dir, fset, packages[dir], nil)
+	}
+
+	return problems
+}
+
+// writeOutput writes content to path, or to stdout when path is "-"
+func writeOutput(path string, content []byte) error {
+	if path == "-" {
+		_, err := os.Stdout.Write(content)
+		return err
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return fmt.Errorf("could not write %s: %w", path, err)
+	}
+	return nil
+}
+
+// firstDifferentLine returns the 1-based line number where a and b first differ
+func firstDifferentLine(a, b []byte) int {
+	linesA := strings.Split(string(a), "\n")
+	linesB := strings.Split(string(b), "\n")
+	for i := 0; i < len(linesA) && i < len(linesB); i++ {
+		if linesA[i] != linesB[i] {
+			return i + 1
+		}
+	}
+	return min(len(linesA), len(linesB)) + 1
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"reddit-go-api-generator/parser"
+	"reddit-go-api-generator/scraper"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigFile is read from the working directory when -config is not given
+const defaultConfigFile = "reddigo.yaml"
+
+// Config holds every generation option. It can be loaded from a YAML file and
+// each field can be overridden by the flag of the same name.
+type Config struct {
+	// Output is the directory the SDK is generated into
+	Output string `yaml:"output"`
+	// Clean removes previously generated files that are no longer produced
+	Clean bool `yaml:"clean"`
+	// Module is the module path used when creating a new go.mod
+	Module string `yaml:"module"`
+	// InParentModule generates the SDK as a package of the module of a parent directory
+	// instead of creating a go.mod in Output
+	InParentModule bool `yaml:"in_parent_module"`
+	// Package is the package name of the generated SDK
+	Package string `yaml:"package"`
+	// GoVersion is written to a newly created go.mod
+	GoVersion string `yaml:"go_version"`
+	// Input is an endpoints JSON file produced by the scrape command.
+	// When empty the Reddit documentation is scraped on every run.
+	Input string `yaml:"input"`
+	// URL is the documentation page to scrape, e.g. a locally saved copy
+	URL string `yaml:"url"`
+	// Limit caps the number of scraped endpoints, zero scrapes all of them
+	Limit int `yaml:"limit"`
+	// LogLevel is one of debug, info, warn or error
+	LogLevel string `yaml:"log_level"`
+	// Report is the file the quality warnings are written to, Markdown when it ends in .md
+	// and JSON otherwise. Empty writes no report.
+	Report string `yaml:"report"`
+	// MaxWarnings fails the run when there are more quality warnings, -1 never fails
+	MaxWarnings int `yaml:"max_warnings"`
+}
+
+func defaultConfig() Config {
+	return Config{
+		Output:      "reddigo",
+		Package:     parser.DefaultPackageName,
+		LogLevel:    "info",
+		MaxWarnings: -1,
+	}
+}
+
+// loadConfig reads a YAML config file on top of the defaults.
+// A missing default config file is not an error; a missing explicit one is.
+func loadConfig(path string) (Config, error) {
+	cfg := defaultConfig()
+
+	explicit := path != ""
+	if !explicit {
+		path = defaultConfigFile
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("could not read config: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return cfg, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// bindFlags registers the shared generation flags on fs, defaulting to the values in cfg
+func (cfg *Config) bindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&cfg.Output, "o", cfg.Output, "Specify the base path for the SDK directory")
+	fs.BoolVar(&cfg.Clean, "clean", cfg.Clean, "Remove previously generated files that are no longer produced")
+	fs.StringVar(&cfg.Module, "module", cfg.Module, "Module path used when creating a new go.mod (default "+DefaultModulePath+")")
+	fs.BoolVar(&cfg.InParentModule, "in-parent-module", cfg.InParentModule, "Generate the SDK as a package of the go.mod found in a parent directory")
+	fs.StringVar(&cfg.Package, "package", cfg.Package, "Package name of the generated SDK")
+	fs.StringVar(&cfg.GoVersion, "go", cfg.GoVersion, "Go version written to a newly created go.mod (defaults to the installed toolchain)")
+	fs.StringVar(&cfg.Input, "input", cfg.Input, "Read endpoints from a JSON file written by the scrape command instead of scraping")
+	fs.StringVar(&cfg.URL, "url", cfg.URL, "Documentation page to scrape (default "+scraper.RedditAPIUrl+")")
+	fs.IntVar(&cfg.Limit, "limit", cfg.Limit, "Maximum number of endpoints to scrape (0 for all)")
+	fs.StringVar(&cfg.Report, "report", cfg.Report, "File to write the quality warnings to, Markdown for .md files and JSON otherwise, or - for stdout")
+	fs.IntVar(&cfg.MaxWarnings, "max-warnings", cfg.MaxWarnings, "Fail when there are more quality warnings than this (-1 never fails)")
+}
+
+// validate checks the options that would otherwise fail late in the pipeline
+func (cfg Config) validate() error {
+	if cfg.Output == "" {
+		return errors.New("you must provide an SDK base path with -o")
+	}
+	if !isValidPackageName(cfg.Package) {
+		return fmt.Errorf("%q is not a valid Go package name", cfg.Package)
+	}
+	if cfg.Limit < 0 {
+		return fmt.Errorf("limit must not be negative, got %d", cfg.Limit)
+	}
+	if cfg.MaxWarnings < -1 {
+		return fmt.Errorf("max-warnings must be -1 or more, got %d", cfg.MaxWarnings)
+	}
+	if _, err := parseLogLevel(cfg.LogLevel); err != nil {
+		return err
+	}
+	return nil
+}
+
+// parseFlags builds the configuration for a subcommand. The config file named by
+// -config (or reddigo.yaml) is loaded first and explicit flags override it.
+// extra registers subcommand specific flags.
+func parseFlags(name string, args []string, extra func(fs *flag.FlagSet)) (Config, error) {
+	newFlagSet := func(cfg *Config, configPath *string, quiet, verbose *bool) *flag.FlagSet {
+		fs := flag.NewFlagSet(name, flag.ContinueOnError)
+		fs.StringVar(configPath, "config", "", "Path to a YAML config file (default "+defaultConfigFile+" if present)")
+		fs.BoolVar(quiet, "quiet", false, "Only log warnings and errors")
+		fs.BoolVar(verbose, "verbose", false, "Log debugging details")
+		cfg.bindFlags(fs)
+		if extra != nil {
+			extra(fs)
+		}
+		return fs
+	}
+
+	// First pass only finds the config file; errors are reported by the second pass
+	var configPath string
+	var quiet, verbose bool
+	probe := defaultConfig()
+	probeSet := newFlagSet(&probe, &configPath, &quiet, &verbose)
+	probeSet.SetOutput(io.Discard)
+	_ = probeSet.Parse(args)
+
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return cfg, withExitCode(exitUsage, err)
+	}
+
+	fs := newFlagSet(&cfg, &configPath, &quiet, &verbose)
+	if err := fs.Parse(args); err != nil {
+		return cfg, withExitCode(exitUsage, err)
+	}
+	if fs.NArg() > 0 {
+		return cfg, withExitCode(exitUsage, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " ")))
+	}
+
+	switch {
+	case quiet && verbose:
+		return cfg, withExitCode(exitUsage, errors.New("-quiet and -verbose are mutually exclusive"))
+	case quiet:
+		cfg.LogLevel = "warn"
+	case verbose:
+		cfg.LogLevel = "debug"
+	}
+
+	if err := cfg.validate(); err != nil {
+		return cfg, withExitCode(exitUsage, err)
+	}
+
+	setupLogger(cfg.LogLevel)
+	return cfg, nil
+}
+
+func parseLogLevel(level string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return l, fmt.Errorf("invalid log level %q: expected debug, info, warn or error", level)
+	}
+	return l, nil
+}
+
+// setupLogger routes the default slog logger to stderr at the given level
+func setupLogger(level string) {
+	l, _ := parseLogLevel(level)
+	handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: l})
+	slog.SetDefault(slog.New(handler))
+}
+
+func isValidPackageName(name string) bool {
+	return name != "_" && token.IsIdentifier(name)
+}
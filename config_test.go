@@ -0,0 +1,48 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseFlagsOverridesConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reddigo.yaml")
+	content := "output: sdk\npackage: redditapi\nmodule: example.com/redditapi\nclean: true\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := parseFlags("generate", []string{"-config", path, "-package", "reddit", "-quiet"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := Config{
+		Output:      "sdk",
+		Clean:       true,
+		Module:      "example.com/redditapi",
+		Package:     "reddit",
+		LogLevel:    "warn",
+		MaxWarnings: -1,
+	}
+	if cfg != expected {
+		t.Errorf("expected %+v but got %+v", expected, cfg)
+	}
+}
+
+func TestParseFlagsRejectsInvalidOptions(t *testing.T) {
+	tests := [][]string{
+		{"-package", "my-sdk"},
+		{"-quiet", "-verbose"},
+		{"-max-warnings", "-2"},
+		{"-config", filepath.Join(t.TempDir(), "missing.yaml")},
+		{"extra"},
+	}
+
+	for _, args := range tests {
+		if _, err := parseFlags("generate", args, nil); err == nil {
+			t.Errorf("expected an error for args %v", args)
+		}
+	}
+}
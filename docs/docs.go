@@ -0,0 +1,207 @@
+package docs
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"reddit-go-api-generator/models"
+	"reddit-go-api-generator/parser"
+	"reddit-go-api-generator/writer"
+	"regexp"
+	"strings"
+	texttemplate "text/template"
+)
+
+// DocumentationURL is the Reddit page every method links back to
+const DocumentationURL = "https://www.reddit.com/dev/api"
+
+// Header marks documentation pages as generated so they can be safely replaced
+const Header = "<!-- Code generated by reddigo-generator. DO NOT EDIT. -->"
+
+// Supported output formats
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+//go:embed templates
+var templates embed.FS
+
+var (
+	slugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+	blankLines  = regexp.MustCompile(`\n{3,}`)
+)
+
+// Options controls the generated reference
+type Options struct {
+	// PackageName is the Go package the methods live in
+	PackageName string
+	// Format is FormatMarkdown or FormatHTML
+	Format string
+}
+
+// Section is one reference page
+type Section struct {
+	Title   string
+	Slug    string
+	Methods []parser.Method
+}
+
+// page is the data every template receives
+type page struct {
+	Header      string
+	PackageName string
+	Sections    []Section
+	Section     Section
+	Extension   string
+}
+
+// Generate renders an index page plus one reference page per documentation section
+func Generate(endpoints []models.Endpoint, opts Options) ([]writer.File, error) {
+	if opts.Format == "" {
+		opts.Format = FormatMarkdown
+	}
+	if opts.PackageName == "" {
+		opts.PackageName = parser.DefaultPackageName
+	}
+
+	extension, render, err := renderer(opts.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	sections := groupSections(parser.DescribeMethods(endpoints))
+	data := page{Header: Header, PackageName: opts.PackageName, Sections: sections, Extension: extension}
+
+	index, err := render("index", data)
+	if err != nil {
+		return nil, err
+	}
+	files := []writer.File{{Name: "index" + extension, Content: index}}
+
+	for _, section := range sections {
+		data.Section = section
+		content, err := render("section", data)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, writer.File{Name: section.Slug + extension, Content: content})
+	}
+
+	return files, nil
+}
+
+// renderer returns the file extension and a render function for format
+func renderer(format string) (string, func(name string, data page) ([]byte, error), error) {
+	switch format {
+	case FormatMarkdown:
+		tmpl, err := texttemplate.New("markdown").Funcs(texttemplate.FuncMap(funcs)).ParseFS(templates, "templates/*.md.tmpl")
+		if err != nil {
+			return "", nil, err
+		}
+		return ".md", func(name string, data page) ([]byte, error) {
+			var buf bytes.Buffer
+			err := tmpl.ExecuteTemplate(&buf, name+".md.tmpl", data)
+
+			// Optional blocks in the templates leave runs of blank lines behind
+			return blankLines.ReplaceAll(buf.Bytes(), []byte("\n\n")), err
+		}, nil
+	case FormatHTML:
+		tmpl, err := template.New("html").Funcs(template.FuncMap(funcs)).ParseFS(templates, "templates/*.html.tmpl")
+		if err != nil {
+			return "", nil, err
+		}
+		return ".html", func(name string, data page) ([]byte, error) {
+			var buf bytes.Buffer
+			err := tmpl.ExecuteTemplate(&buf, name+".html.tmpl", data)
+			return buf.Bytes(), err
+		}, nil
+	}
+
+	return "", nil, fmt.Errorf("unknown docs format %q: expected %s or %s", format, FormatMarkdown, FormatHTML)
+}
+
+// groupSections buckets methods by documentation section in order of first appearance.
+// Methods without a section go to a page titled other. Every page gets its own slug, so
+// titles with the same slug, or a title slugged index, never overwrite another page.
+func groupSections(methods []parser.Method) []Section {
+	var sections []Section
+	index := make(map[string]int)
+	slugs := map[string]bool{"index": true}
+
+	for _, method := range methods {
+		section := method.Endpoint.Section
+		i, ok := index[section]
+		if !ok {
+			title := section
+			if title == "" {
+				title = "other"
+			}
+			i = len(sections)
+			index[section] = i
+			sections = append(sections, Section{Title: title, Slug: claimSlug(slugs, slug(title))})
+		}
+		sections[i].Methods = append(sections[i].Methods, method)
+	}
+
+	return sections
+}
+
+// claimSlug returns slug, or slug with the smallest free numeric suffix when it is taken,
+// e.g. links-and-comments-2, and marks it taken
+func claimSlug(taken map[string]bool, slug string) string {
+	if slug == "" {
+		slug = "section"
+	}
+	claimed := slug
+	for i := 2; taken[claimed]; i++ {
+		claimed = fmt.Sprintf("%s-%d", slug, i)
+	}
+	taken[claimed] = true
+	return claimed
+}
+
+// slug turns "links & comments" into links-and-comments
+func slug(title string) string {
+	title = strings.ReplaceAll(strings.ToLower(title), "&", " and ")
+	return strings.Trim(slugPattern.ReplaceAllString(title, "-"), "-")
+}
+
+var funcs = map[string]any{
+	"docsURL": func() string {
+		return DocumentationURL
+	},
+	// header emits the generated marker verbatim; html/template would otherwise escape the comment away
+	"header": func(header string) template.HTML {
+		return template.HTML(header)
+	},
+	// title pairs page data with a page title for the shared HTML layout
+	"title": func(data page, title string) map[string]any {
+		return map[string]any{"Header": data.Header, "Title": title}
+	},
+	"sourceURL": func(endpoint models.Endpoint) string {
+		if endpoint.Anchor == "" {
+			return DocumentationURL
+		}
+		return DocumentationURL + "#" + endpoint.Anchor
+	},
+	"anchor": func(name string) string {
+		return strings.ToLower(name)
+	},
+	// path shows an optional subreddit prefix the way the Reddit docs do
+	"path": func(endpoint models.Endpoint) string {
+		if endpoint.OptionalSubreddit {
+			return "[/r/{subreddit}]" + endpoint.Path
+		}
+		return endpoint.Path
+	},
+	"join": strings.Join,
+	"oneLine": func(text string) string {
+		return strings.Join(strings.Fields(text), " ")
+	},
+	"cell": func(text string) string {
+		return strings.ReplaceAll(strings.Join(strings.Fields(text), " "), "|", `\|`)
+	},
+	"slug": slug,
+}
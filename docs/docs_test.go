@@ -0,0 +1,154 @@
+package docs
+
+import (
+	"reddit-go-api-generator/models"
+	"reddit-go-api-generator/parser"
+	"reddit-go-api-generator/writer"
+	"strings"
+	"testing"
+)
+
+var testEndpoints = []models.Endpoint{
+	{
+		ID:          "GET /api/v1/me",
+		Method:      "GET",
+		Path:        "/api/v1/me",
+		Section:     "account",
+		Anchor:      "GET_api_v1_me",
+		Scopes:      []string{"identity"},
+		Description: "Returns the identity of the user.",
+	},
+	{
+		ID:                "GET /hot",
+		Method:            "GET",
+		Path:              "/hot",
+		OptionalSubreddit: true,
+		Section:           "links & comments",
+		Description:       "A <listing>.",
+		QueryParams: []models.Parameter{
+			{Name: "g", Description: "one of (GLOBAL, US)", Type: models.EnumOf("GLOBAL", "US")},
+		},
+		Response: []models.Output{
+			{Name: "g", Description: "one of (GLOBAL, US)", Type: models.EnumOf("GLOBAL", "US")},
+		},
+	},
+	{
+		ID:     "DELETE /api/mod/conversations/{conversation_id}/highlight",
+		Method: "DELETE",
+		Path:   "/api/mod/conversations/{conversation_id}/highlight",
+	},
+}
+
+func TestGenerateMarkdown(t *testing.T) {
+	files, err := Generate(testEndpoints, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pages := make(map[string]string)
+	for _, file := range files {
+		if !writer.IsGenerated(file.Content) {
+			t.Errorf("%s is missing the generated header", file.Name)
+		}
+		pages[file.Name] = string(file.Content)
+	}
+
+	for _, name := range []string{"index.md", "account.md", "links-and-comments.md", "other.md"} {
+		if _, ok := pages[name]; !ok {
+			t.Fatalf("expected page %s, got %v", name, len(pages))
+		}
+	}
+
+	expected := []string{
+		"[links & comments](links-and-comments.md)",
+		"func (sdk *ReddiGoSDK) GetMe() (any, error)",
+		"([documentation](https://www.reddit.com/dev/api#GET_api_v1_me))",
+		"- OAuth scopes: `identity`",
+	}
+	for _, want := range expected {
+		if !strings.Contains(pages["index.md"]+pages["account.md"], want) {
+			t.Errorf("expected the markdown to contain %q", want)
+		}
+	}
+
+	if !strings.Contains(pages["links-and-comments.md"], "- Reddit endpoint: `GET [/r/{subreddit}]/hot`") {
+		t.Errorf("expected the optional subreddit prefix to be shown:\n%s", pages["links-and-comments.md"])
+	}
+	if !strings.Contains(pages["links-and-comments.md"], "| `GetHotGEnum` | `GLOBAL`, `US` |") {
+		t.Errorf("expected the enum values to be listed:\n%s", pages["links-and-comments.md"])
+	}
+	if strings.Contains(pages["links-and-comments.md"], "\n\n\n") {
+		t.Errorf("unexpected run of blank lines:\n%s", pages["links-and-comments.md"])
+	}
+}
+
+func TestGenerateHTML(t *testing.T) {
+	files, err := Generate(testEndpoints, Options{Format: FormatHTML, PackageName: "reddit"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var section string
+	for _, file := range files {
+		if !writer.IsGenerated(file.Content) {
+			t.Errorf("%s is missing the generated header", file.Name)
+		}
+		if file.Name == "links-and-comments.html" {
+			section = string(file.Content)
+		}
+	}
+
+	if !strings.Contains(section, "<p>A &lt;listing&gt;.</p>") {
+		t.Errorf("expected descriptions to be escaped:\n%s", section)
+	}
+	if !strings.Contains(section, `<h2 id="gethot">GetHot</h2>`) {
+		t.Errorf("expected a method heading:\n%s", section)
+	}
+}
+
+func TestGenerateRejectsUnknownFormat(t *testing.T) {
+	if _, err := Generate(testEndpoints, Options{Format: "pdf"}); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestSectionSlugsAreUnique(t *testing.T) {
+	endpoints := []models.Endpoint{
+		{ID: "GET /api/info", Method: "GET", Path: "/api/info", Section: "links & comments"},
+		{ID: "POST /api/comment", Method: "POST", Path: "/api/comment", Section: "links and comments"},
+		{ID: "GET /api/v1/me", Method: "GET", Path: "/api/v1/me", Section: "Index"},
+		{ID: "GET /api/v1/scopes", Method: "GET", Path: "/api/v1/scopes", Section: "other"},
+		{ID: "GET /hot", Method: "GET", Path: "/hot"},
+		{ID: "GET /new", Method: "GET", Path: "/new", Section: "&"},
+	}
+
+	tests := []struct {
+		title    string
+		expected string
+	}{
+		{"links & comments", "links-and-comments"},
+		{"links and comments", "links-and-comments-2"},
+		{"Index", "index-2"},
+		{"other", "other"},
+		{"other", "other-2"},
+		{"&", "and"},
+	}
+
+	sections := groupSections(parser.DescribeMethods(endpoints))
+	if len(sections) != len(tests) {
+		t.Fatalf("expected %d sections, got %+v", len(tests), sections)
+	}
+	for i, test := range tests {
+		if sections[i].Title != test.title || sections[i].Slug != test.expected {
+			t.Errorf("For input '%s', expected '%s' but got '%s' (%s)", test.title, test.expected, sections[i].Slug, sections[i].Title)
+		}
+	}
+
+	files, err := Generate(endpoints, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != len(tests)+1 {
+		t.Errorf("expected an index and %d pages, got %d files", len(tests), len(files))
+	}
+}
@@ -7,6 +7,7 @@ toolchain go1.23.2
 require (
 	github.com/PuerkitoBio/goquery v1.10.0
 	github.com/gocolly/colly v1.2.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
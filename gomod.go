@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultModulePath is the module created when the output directory is not inside a module
+const DefaultModulePath = "github.com/stationFortyTwo/ReddiGo"
+
+// moduleInfo describes the Go module that contains the output directory
+type moduleInfo struct {
+	Root string
+	Path string
+}
+
+// findModule walks up from dir looking for a go.mod and returns the module it declares.
+// ok is false when dir is not inside any module.
+func findModule(dir string) (info moduleInfo, ok bool, err error) {
+	current, err := filepath.Abs(dir)
+	if err != nil {
+		return moduleInfo{}, false, err
+	}
+
+	for {
+		goMod := filepath.Join(current, "go.mod")
+		if _, err := os.Stat(goMod); err == nil {
+			modulePath, err := readModulePath(goMod)
+			if err != nil {
+				return moduleInfo{}, false, err
+			}
+			return moduleInfo{Root: current, Path: modulePath}, true, nil
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return moduleInfo{}, false, nil
+		}
+		current = parent
+	}
+}
+
+// readModulePath returns the path from the module directive of a go.mod file
+func readModulePath(goMod string) (string, error) {
+	file, err := os.Open(goMod)
+	if err != nil {
+		return "", fmt.Errorf("could not read %s: %w", goMod, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("could not read %s: %w", goMod, err)
+	}
+
+	return "", fmt.Errorf("no module directive in %s", goMod)
+}
+
+// resolveModule returns the module the SDK in basePath belongs to. Only a go.mod in basePath
+// itself is reused, unless inParent allows generating into the module of a parent directory.
+// ok is false when a new module has to be created. A modulePath other than the path of the
+// existing module is an error.
+func resolveModule(basePath string, modulePath string, inParent bool) (info moduleInfo, ok bool, err error) {
+	absBase, err := filepath.Abs(basePath)
+	if err != nil {
+		return moduleInfo{}, false, err
+	}
+
+	if inParent {
+		info, ok, err = findModule(absBase)
+	} else if _, statErr := os.Stat(filepath.Join(absBase, "go.mod")); statErr == nil {
+		info.Root = absBase
+		info.Path, err = readModulePath(filepath.Join(absBase, "go.mod"))
+		ok = err == nil
+	}
+	if err != nil || !ok {
+		return moduleInfo{}, false, err
+	}
+
+	if modulePath != "" && modulePath != info.Path {
+		return moduleInfo{}, false, fmt.Errorf("module %s was requested but %s already declares %s", modulePath, filepath.Join(info.Root, "go.mod"), info.Path)
+	}
+	return info, true, nil
+}
+
+// ensureGoModule makes sure the SDK directory belongs to a Go module. The module found by
+// resolveModule is kept and a new one is created otherwise.
+func ensureGoModule(basePath string, modulePath string, goVersion string, inParent bool) error {
+	existing, ok, err := resolveModule(basePath, modulePath, inParent)
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		absBase, err := filepath.Abs(basePath)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(existing.Root, absBase)
+		if err != nil {
+			return err
+		}
+
+		importPath := existing.Path
+		if rel != "." {
+			importPath += "/" + filepath.ToSlash(rel)
+		}
+		slog.Info("Using existing module", "module", existing.Path, "import", importPath)
+		return nil
+	}
+
+	if modulePath == "" {
+		modulePath = DefaultModulePath
+	}
+
+	return initGoModule(basePath, modulePath, goVersion)
+}
+
+// initGoModule initializes a new Go module in the specified directory
+func initGoModule(basePath string, moduleName string, goVersion string) error {
+	cmd := exec.Command("go", "mod", "init", moduleName)
+	tidyCmd := exec.Command("go", "mod", "tidy")
+
+	cmd.Dir = basePath     // Set the directory where the command should run
+	tidyCmd.Dir = basePath // Set the directory where the command should run
+
+	// Run the command and capture output
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to initialize go module: %w, output: %s", err, string(output))
+	}
+
+	if goVersion != "" {
+		editCmd := exec.Command("go", "mod", "edit", "-go="+goVersion)
+		editCmd.Dir = basePath
+
+		outputEdit, err := editCmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("failed to set go version: %w, outputEdit: %s", err, string(outputEdit))
+		}
+	}
+
+	outputTidy, err := tidyCmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to initialize go module: %w, outputTidy: %s", err, string(outputTidy))
+	}
+
+	slog.Info("Go module initialized", "module", moduleName)
+	return nil
+}
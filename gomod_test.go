@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindModule(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/bot\n\ngo 1.23\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(root, "internal", "reddigo")
+	if err := os.MkdirAll(sub, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	info, ok, err := findModule(sub)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected to find the enclosing module")
+	}
+	if info.Path != "example.com/bot" || info.Root != root {
+		t.Errorf("expected module example.com/bot at %s, got %+v", root, info)
+	}
+}
+
+func TestResolveModule(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/bot\n\ngo 1.23\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(root, "reddigo")
+	if err := os.MkdirAll(sub, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		dir        string
+		modulePath string
+		inParent   bool
+		expected   string
+		wantErr    bool
+	}{
+		{name: "module in the output directory", dir: root, expected: "example.com/bot"},
+		{name: "same module requested", dir: root, modulePath: "example.com/bot", expected: "example.com/bot"},
+		{name: "different module requested", dir: root, modulePath: "example.com/sdk", wantErr: true},
+		{name: "parent module ignored", dir: sub, modulePath: "example.com/sdk"},
+		{name: "parent module allowed", dir: sub, inParent: true, expected: "example.com/bot"},
+		{name: "parent module conflicts", dir: sub, modulePath: "example.com/sdk", inParent: true, wantErr: true},
+	}
+
+	for _, test := range tests {
+		info, ok, err := resolveModule(test.dir, test.modulePath, test.inParent)
+		if (err != nil) != test.wantErr {
+			t.Errorf("For input '%s', expected error %v but got '%v'", test.name, test.wantErr, err)
+			continue
+		}
+		if ok != (test.expected != "") || info.Path != test.expected {
+			t.Errorf("For input '%s', expected module '%s' but got '%+v'", test.name, test.expected, info)
+		}
+	}
+}
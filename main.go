@@ -1,175 +1,124 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"os"
-	"os/exec"
-	"path/filepath"
-	"reddit-go-api-generator/parser"
-	"reddit-go-api-generator/scraper"
+	"runtime/debug"
 )
 
-// "https://www.reddit.com/dev/api/"
-
-// CountEndpointsOnRedditAPI counts the number of endpoints on the Reddit API documentation page
-//func CountEndpointsOnRedditAPI() (int, error) {
-//	count := 0
-//	c := colly.NewCollector()
-//
-//	// Count each endpoint section
-//	c.OnHTML("div.endpoint", func(e *colly.HTMLElement) {
-//		count++
-//		// log.Printf("Endpoint found: %s %s", e.ChildText("h3 span.method"), e.ChildText("h3 em.placeholder"))
-//	})
-//
-//	// Visit the API documentation page
-//	err := c.Visit(scraper.RedditAPIUrl)
-//	if err != nil {
-//		log.Fatalf("Error visiting URL: %v", err)
-//		return 0, err
-//	}
-//
-//	return count, nil
-//}
-
-// setupSDKDirectory creates the directory structure for the SDK if it does not exist
-func setupSDKDirectory(basePath string) (string, error) {
-	// Define the SDK directory path
-	sdkDir := filepath.Join(basePath)
-
-	if _, err := os.Stat(sdkDir); err == nil {
-		// Directory exists, so delete it
-		if err := os.RemoveAll(sdkDir); err != nil {
-			return "", fmt.Errorf("could not delete existing SDK directory: %w", err)
-		}
-	}
+// version is set at build time with -ldflags "-X main.version=v1.2.3"
+var version = "dev"
+
+// Exit codes shared by every subcommand
+const (
+	exitOK       = 0
+	exitError    = 1
+	exitUsage    = 2
+	exitDiff     = 3
+	exitInvalid  = 4
+	exitWarnings = 5
+)
 
-	// Create the SDK directory if it does not exist
-	if err := os.MkdirAll(sdkDir, os.ModePerm); err != nil {
-		return "", fmt.Errorf("could not create SDK directory structure: %w", err)
-	}
+// command is a single CLI subcommand
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
 
-	// Return the final path where the generated file should be placed
-	return filepath.Join(sdkDir, "reddigo.go"), nil
+var commands = []command{
+	{"scrape", "Scrape the Reddit API documentation into an endpoints JSON file", runScrape},
+	{"generate", "Generate the Go SDK into the output directory", runGenerate},
+	{"diff", "Show which generated files would change without writing anything", runDiff},
+	{"validate", "Generate the SDK in memory and type-check it", runValidate},
+	{"docs", "Write Markdown or HTML reference pages for the generated SDK", runDocs},
+	{"openapi", "Export the scraped endpoints as an OpenAPI 3.1 document", runOpenAPI},
+	{"version", "Print the generator version", runVersion},
 }
 
-func main() {
-	endpointsData, err := scraper.ScrapeRedditAPI(0,
-		func(s string) {
-			//log.Printf("Targeted: %s", s)
-		},
-		func(s string) {
-			//log.Printf("Processed: %s", s)
-		},
-	)
-
-	if err != nil {
-		log.Printf("Error: %v", err)
-	} else {
-		log.Printf("Successfully scraped %d endpointsData", len(endpointsData))
-	}
+// exitCodeError carries a specific exit code out of a subcommand
+type exitCodeError struct {
+	code int
+	err  error
+}
 
-	finalFunctions := parser.GenerateGoFunctions(endpointsData)
+func (e *exitCodeError) Error() string { return e.err.Error() }
+func (e *exitCodeError) Unwrap() error { return e.err }
 
-	for _, function := range finalFunctions {
-		println(function)
-	}
+func withExitCode(code int, err error) error {
+	return &exitCodeError{code: code, err: err}
+}
 
-	sdkPath := collectSDKFilePath()
-	outputFileFinal, err := setupSDKDirectory(sdkPath)
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
 
-	if err != nil {
-		log.Fatalf("Error setting up SDK directory: %v", err)
+func run(args []string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		usage(os.Stderr)
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
 	}
 
-	err = writeToFile(outputFileFinal, finalFunctions)
-	if err != nil {
-		log.Fatalf("Error writing to file: %v", err)
-	}
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
 
-	// Initialize the Go module after writing the file
-	err = initGoModule(sdkPath, "github.com/stationFortyTwo/ReddiGo")
-	if err != nil {
-		log.Fatalf("Error initializing Go module: %v", err)
-	}
+		err := cmd.run(args[1:])
+		if err == nil {
+			return exitOK
+		}
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
 
-	// Iterate through the collected endpoints and print them
-	for _, endpoint := range endpointsData {
-		fmt.Printf("Endpoint: %+v\n", endpoint)
-	}
+		var codeErr *exitCodeError
+		if errors.As(err, &codeErr) {
+			if codeErr.code != exitDiff {
+				slog.Error(codeErr.Error())
+			}
+			return codeErr.code
+		}
 
-	log.Println("Successfully built ReddiGo SDK")
+		slog.Error(err.Error())
+		return exitError
+	}
 
-	os.Exit(0)
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[0])
+	usage(os.Stderr)
+	return exitUsage
 }
 
-// initGoModule initializes a new Go module in the specified directory
-func initGoModule(basePath string, moduleName string) error {
-	cmd := exec.Command("go", "mod", "init", moduleName)
-	tidyCmd := exec.Command("go", "mod", "tidy")
-
-	cmd.Dir = basePath     // Set the directory where the command should run
-	tidyCmd.Dir = basePath // Set the directory where the command should run
-
-	// Run the command and capture output
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to initialize go module: %w, output: %s", err, string(output))
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: reddigo-generator <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
 	}
-
-	outputTidy, err := tidyCmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to initialize go module: %w, outputTidy: %s", err, string(outputTidy))
-	}
-
-	fmt.Printf("Go module initialized: %s\n", string(output))
-	return nil
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'reddigo-generator <command> -h' for the flags of a command.")
+	fmt.Fprintf(w, "Options are read from %s when present; flags take precedence.\n", defaultConfigFile)
 }
 
-// writeToFile writes the generated Go functions to a file
-func writeToFile(filename string, functions []string) error {
-	// Create or open the file
-	file, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("could not create file: %w", err)
+func runVersion(args []string) error {
+	fs := flag.NewFlagSet("version", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return withExitCode(exitUsage, err)
 	}
-	defer file.Close()
 
-	// Write each function to the file
-	for _, function := range functions {
-		_, err := file.WriteString(function + "\n\n")
-		if err != nil {
-			return fmt.Errorf("could not write to file: %w", err)
-		}
+	v := version
+	if info, ok := debug.ReadBuildInfo(); ok && v == "dev" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		v = info.Main.Version
 	}
 
+	fmt.Printf("reddigo-generator %s\n", v)
 	return nil
 }
-
-func collectSDKFilePath() string {
-	// Define a flag to collect the SDK base path
-	sdkPath := flag.String("o", "reddigo", "Specify the base path for the SDK directory")
-
-	// Parse the flags
-	flag.Parse()
-
-	// Check if the -sdkPath flag is provided and has a value
-	if *sdkPath == "" {
-		log.Fatal("Error: You must provide an SDK base path with -sdkPath")
-	}
-
-	//// Construct the SDK file path
-	//filePath, err := setupSDKDirectory(*sdkPath)
-	//if err != nil {
-	//	log.Fatalf("Error setting up SDK directory: %v", err)
-	//}
-	//
-	//// Print the path for confirmation
-	//fmt.Printf("SDK file path: %s\n", filePath)
-
-	//return &filePath
-
-	return *sdkPath
-}
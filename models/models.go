@@ -1,42 +1,51 @@
 package models
 
-type RedditAPIField struct {
-	Name        string
-	Description string
-	Type        string
-}
-
 type Endpoint struct {
-	ID          string
-	Method      string
-	Path        string
-	Description string
-	URLParams   []string
-	Payload     []Input
-	Response    []Output
-	QueryParams []Parameter
+	ID                string
+	Method            string
+	Path              string
+	OptionalSubreddit bool     // documented as [/r/subreddit]/path, so it can also be called below /r/{subreddit}
+	Variants          []string // other documented paths, e.g. /about/banned for /about/{where}
+	Section           string
+	Anchor            string   // id of the endpoint on the documentation page, e.g. POST_api_comment
+	Scopes            []string // OAuth scopes required to call the endpoint
+	Description       string
+	URLParams         []string
+	Payload           []Input
+	Response          []Output
+	QueryParams       []Parameter
 }
 
-type Input struct {
+// Field is a named, typed value documented for an endpoint
+type Field struct {
 	Name        string
 	Description string
-	Type        string
+	Type        TypeRef
+	TypeRule    string       `json:",omitempty"` // name of the scraper rule that inferred Type, empty when untyped
+	Constraints *Constraints `json:",omitempty"` // limits documented for the value, nil when there are none
 }
 
-type Output struct {
-	Name        string
-	Description string
-	Type        string
+// Constraints are the limits documented for a field. Zero lengths and nil bounds mean no limit.
+// Allowed values are not repeated here, they are the Values of an enum TypeRef.
+type Constraints struct {
+	MinLength int      `json:",omitempty"` // in characters
+	MaxLength int      `json:",omitempty"` // in characters
+	Min       *float64 `json:",omitempty"`
+	Max       *float64 `json:",omitempty"`
 }
 
-type Parameter struct {
-	Name        string
-	Description string
-	Type        string
-}
+// Input is a field of the request body
+type Input = Field
+
+// Output is a field of the response
+type Output = Field
+
+// Parameter is a query string parameter
+type Parameter = Field
 
 // Struct to represent enums
 type Enum struct {
-	Name   string
-	Values []string
+	Name      string
+	Values    []string
+	Constants []string // Go constant declared for each value, in the same order
 }
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// TypeKind classifies a TypeRef
+type TypeKind string
+
+const (
+	KindAny       TypeKind = ""          // not documented or not recognised
+	KindString    TypeKind = "string"    // primitive
+	KindInt       TypeKind = "int"       // primitive
+	KindFloat     TypeKind = "float"     // primitive
+	KindBool      TypeKind = "bool"      // primitive
+	KindEnum      TypeKind = "enum"      // a string limited to Values
+	KindArray     TypeKind = "array"     // a list of Elem
+	KindObject    TypeKind = "object"    // a JSON object with Fields
+	KindFullname  TypeKind = "fullname"  // a thing fullname such as t3_15bfi0
+	KindTimestamp TypeKind = "timestamp" // seconds since the epoch
+
+	KindBoolOrTimestamp TypeKind = "bool_or_timestamp" // false when unset, a timestamp otherwise, e.g. edited
+	KindStringOrNumber  TypeKind = "string_or_number"  // sent as either a JSON string or a number
+)
+
+// TypeRef is the type of an input, output or parameter
+type TypeRef struct {
+	Kind   TypeKind `json:"kind,omitempty"`
+	Values []string `json:"values,omitempty"` // allowed values of an enum
+	Elem   *TypeRef `json:"elem,omitempty"`   // element type of an array
+	Fields []Field  `json:"fields,omitempty"` // members of an object, in documentation order
+}
+
+// Primitive returns the TypeRef of a kind without parameters, e.g. KindString or KindFullname
+func Primitive(kind TypeKind) TypeRef {
+	return TypeRef{Kind: kind}
+}
+
+// EnumOf returns an enum type allowing values
+func EnumOf(values ...string) TypeRef {
+	return TypeRef{Kind: KindEnum, Values: values}
+}
+
+// ArrayOf returns an array type with elements of elem
+func ArrayOf(elem TypeRef) TypeRef {
+	return TypeRef{Kind: KindArray, Elem: &elem}
+}
+
+// ObjectOf returns an object type with fields
+func ObjectOf(fields ...Field) TypeRef {
+	return TypeRef{Kind: KindObject, Fields: fields}
+}
+
+// String renders t the way the documentation would, e.g. enum(a, b) or array(string)
+func (t TypeRef) String() string {
+	switch t.Kind {
+	case KindAny:
+		return "any"
+	case KindEnum:
+		return "enum(" + strings.Join(t.Values, ", ") + ")"
+	case KindArray:
+		if t.Elem == nil {
+			return "array"
+		}
+		return "array(" + t.Elem.String() + ")"
+	}
+	return string(t.Kind)
+}
+
+// ParseTypeRef reads the string form of a type, as written by String or by older versions
+// of the scraper, e.g. "int", "interface{}" or "enum(a, b)". Types it does not recognise are
+// left untyped, so the field is reported rather than generated as an undeclared type.
+func ParseTypeRef(s string) TypeRef {
+	s = strings.TrimSpace(s)
+
+	switch {
+	case s == "" || s == "any" || s == "interface{}":
+		return TypeRef{}
+	case strings.HasPrefix(s, "enum(") && strings.HasSuffix(s, ")"):
+		var values []string
+		for _, value := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(s, "enum("), ")"), ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+		return EnumOf(values...)
+	case strings.HasPrefix(s, "array(") && strings.HasSuffix(s, ")"):
+		return ArrayOf(ParseTypeRef(strings.TrimSuffix(strings.TrimPrefix(s, "array("), ")")))
+	case strings.HasPrefix(s, "[]"):
+		return ArrayOf(ParseTypeRef(strings.TrimPrefix(s, "[]")))
+	}
+
+	switch kind := TypeKind(s); kind {
+	case KindString, KindInt, KindFloat, KindBool, KindArray, KindObject, KindFullname, KindTimestamp,
+		KindBoolOrTimestamp, KindStringOrNumber:
+		return Primitive(kind)
+	}
+	return TypeRef{}
+}
+
+// UnmarshalJSON accepts both the object form and the plain strings older endpoint files hold
+func (t *TypeRef) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*t = ParseTypeRef(s)
+		return nil
+	}
+
+	type plain TypeRef
+	return json.Unmarshal(data, (*plain)(t))
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseTypeRef(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected TypeRef
+	}{
+		{"string", Primitive(KindString)},
+		{"int", Primitive(KindInt)},
+		{"interface{}", TypeRef{}},
+		{"", TypeRef{}},
+		{"enum(GLOBAL, US)", EnumOf("GLOBAL", "US")},
+		{"array(fullname)", ArrayOf(Primitive(KindFullname))},
+		{"[]string", ArrayOf(Primitive(KindString))},
+		{"timestamp", Primitive(KindTimestamp)},
+		{"bool_or_timestamp", Primitive(KindBoolOrTimestamp)},
+		{"Listing", TypeRef{}},
+	}
+
+	for _, test := range tests {
+		output := ParseTypeRef(test.input)
+		if !reflect.DeepEqual(output, test.expected) {
+			t.Errorf("For input '%s', expected '%+v' but got '%+v'", test.input, test.expected, output)
+		}
+		if roundTrip := ParseTypeRef(output.String()); !reflect.DeepEqual(roundTrip, test.expected) {
+			t.Errorf("For input '%s', expected '%s' to parse back but got '%+v'", test.input, output, roundTrip)
+		}
+	}
+}
+
+func TestTypeRefJSON(t *testing.T) {
+	field := Field{Name: "styles", Type: ObjectOf(Field{Name: "colors", Type: ArrayOf(EnumOf("red", "blue"))})}
+
+	content, err := json.Marshal(field)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Field
+	if err := json.Unmarshal(content, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, field) {
+		t.Errorf("expected %+v after a round trip but got %+v", field, decoded)
+	}
+
+	// Endpoint files written before TypeRef hold plain strings
+	var legacy Field
+	if err := json.Unmarshal([]byte(`{"Name": "g", "Type": "enum(GLOBAL, US)"}`), &legacy); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(legacy.Type, EnumOf("GLOBAL", "US")) {
+		t.Errorf("expected the legacy enum to be parsed, got %+v", legacy.Type)
+	}
+}
@@ -0,0 +1,356 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reddit-go-api-generator/models"
+	"reddit-go-api-generator/parser"
+	"regexp"
+	"strings"
+)
+
+// Version is the OpenAPI specification version of the exported document
+const Version = "3.1.0"
+
+const (
+	serverURL        = "https://oauth.reddit.com"
+	documentationURL = "https://www.reddit.com/dev/api"
+	authorizeURL     = "https://www.reddit.com/api/v1/authorize"
+	tokenURL         = "https://www.reddit.com/api/v1/access_token"
+	securityScheme   = "oauth2"
+)
+
+var placeholderPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// Document is the subset of an OpenAPI 3.1 document the exporter produces
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers"`
+	Tags       []Tag                `json:"tags,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+type Tag struct {
+	Name string `json:"name"`
+}
+
+type ExternalDocs struct {
+	URL string `json:"url"`
+}
+
+// PathItem holds the operations available on one path
+type PathItem struct {
+	Get    *Operation `json:"get,omitempty"`
+	Put    *Operation `json:"put,omitempty"`
+	Post   *Operation `json:"post,omitempty"`
+	Delete *Operation `json:"delete,omitempty"`
+	Patch  *Operation `json:"patch,omitempty"`
+}
+
+type Operation struct {
+	OperationID  string                `json:"operationId"`
+	Summary      string                `json:"summary,omitempty"`
+	Description  string                `json:"description,omitempty"`
+	Tags         []string              `json:"tags,omitempty"`
+	Parameters   []Parameter           `json:"parameters,omitempty"`
+	RequestBody  *RequestBody          `json:"requestBody,omitempty"`
+	Responses    map[string]Response   `json:"responses"`
+	Security     []map[string][]string `json:"security,omitempty"`
+	ExternalDocs *ExternalDocs         `json:"externalDocs,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Content map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is a JSON Schema (2020-12) as used by OpenAPI 3.1
+type Schema struct {
+	Type        string             `json:"type,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	OneOf       []*Schema          `json:"oneOf,omitempty"`
+	MinLength   int                `json:"minLength,omitempty"`
+	MaxLength   int                `json:"maxLength,omitempty"`
+	Minimum     *float64           `json:"minimum,omitempty"`
+	Maximum     *float64           `json:"maximum,omitempty"`
+}
+
+type Components struct {
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type  string     `json:"type"`
+	Flows OAuthFlows `json:"flows"`
+}
+
+type OAuthFlows struct {
+	AuthorizationCode *OAuthFlow `json:"authorizationCode,omitempty"`
+}
+
+type OAuthFlow struct {
+	AuthorizationURL string            `json:"authorizationUrl"`
+	TokenURL         string            `json:"tokenUrl"`
+	Scopes           map[string]string `json:"scopes"`
+}
+
+// Export turns scraped endpoints into an OpenAPI document. Every path variant gets an
+// operation, and endpoints with an optional [/r/subreddit] prefix are exported both with
+// and without it. Endpoints sharing a method and path keep the first documented entry.
+func Export(endpoints []models.Endpoint) *Document {
+	doc := &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:       "Reddit API",
+			Description: "Generated from " + documentationURL,
+			Version:     "1.0.0",
+		},
+		Servers: []Server{{URL: serverURL}},
+		Paths:   make(map[string]*PathItem),
+	}
+
+	scopes := make(map[string]string)
+	seenTags := make(map[string]bool)
+
+	endpoints = parser.ExpandVariants(endpoints)
+	operationIDs := parser.FunctionNames(endpoints)
+	// The method names are unique, so only the ids of subreddit paths can collide
+	usedIDs := make(map[string]bool, len(operationIDs))
+	for _, operationID := range operationIDs {
+		usedIDs[operationID] = true
+	}
+	for i, endpoint := range endpoints {
+		for j, path := range parser.CallablePaths(endpoint) {
+			item, ok := doc.Paths[path]
+			if !ok {
+				item = &PathItem{}
+				doc.Paths[path] = item
+			}
+
+			slot := item.operation(endpoint.Method)
+			if slot == nil || *slot != nil {
+				continue
+			}
+
+			operationID := operationIDs[i]
+			if j > 0 {
+				operationID = claimOperationID(usedIDs, operationID+"InSubreddit")
+			}
+			*slot = buildOperation(endpoint, path, operationID)
+		}
+
+		for _, scope := range endpoint.Scopes {
+			scopes[scope] = "Reddit OAuth scope " + scope
+		}
+		if endpoint.Section != "" && !seenTags[endpoint.Section] {
+			seenTags[endpoint.Section] = true
+			doc.Tags = append(doc.Tags, Tag{Name: endpoint.Section})
+		}
+	}
+
+	doc.Components.SecuritySchemes = map[string]SecurityScheme{
+		securityScheme: {
+			Type: "oauth2",
+			Flows: OAuthFlows{AuthorizationCode: &OAuthFlow{
+				AuthorizationURL: authorizeURL,
+				TokenURL:         tokenURL,
+				Scopes:           scopes,
+			}},
+		},
+	}
+
+	return doc
+}
+
+// Marshal renders the document as indented JSON, which is also valid YAML
+func (d *Document) Marshal() ([]byte, error) {
+	content, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("could not encode OpenAPI document: %w", err)
+	}
+	return append(content, '\n'), nil
+}
+
+// claimOperationID returns id, or id with the first free numeric suffix when it is used
+func claimOperationID(used map[string]bool, id string) string {
+	claimed := id
+	for n := 2; used[claimed]; n++ {
+		claimed = fmt.Sprintf("%s%d", id, n)
+	}
+	used[claimed] = true
+	return claimed
+}
+
+// operation returns the field of the path item holding method, or nil for unsupported methods
+func (p *PathItem) operation(method string) **Operation {
+	switch strings.ToUpper(method) {
+	case "GET":
+		return &p.Get
+	case "PUT":
+		return &p.Put
+	case "POST":
+		return &p.Post
+	case "DELETE":
+		return &p.Delete
+	case "PATCH":
+		return &p.Patch
+	}
+	return nil
+}
+
+func buildOperation(endpoint models.Endpoint, path string, operationID string) *Operation {
+	op := &Operation{
+		OperationID: operationID,
+		Summary:     summarize(endpoint.Description),
+		Description: endpoint.Description,
+		Responses:   map[string]Response{"200": buildResponse(endpoint)},
+	}
+
+	if endpoint.Section != "" {
+		op.Tags = []string{endpoint.Section}
+	}
+	if endpoint.Anchor != "" {
+		op.ExternalDocs = &ExternalDocs{URL: documentationURL + "#" + endpoint.Anchor}
+	}
+	if len(endpoint.Scopes) > 0 {
+		op.Security = []map[string][]string{{securityScheme: endpoint.Scopes}}
+	}
+
+	for _, match := range placeholderPattern.FindAllStringSubmatch(path, -1) {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+	for _, param := range endpoint.QueryParams {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:        param.Name,
+			In:          "query",
+			Description: param.Description,
+			Schema:      schemaFor(param.Type, "", param.Constraints),
+		})
+	}
+
+	if len(endpoint.Payload) > 0 {
+		body := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		for _, input := range endpoint.Payload {
+			body.Properties[input.Name] = schemaFor(input.Type, input.Description, input.Constraints)
+		}
+		// A lone json input is the whole body, e.g. an array parsed from a JSON model
+		if len(endpoint.Payload) == 1 && endpoint.Payload[0].Name == "json" {
+			body = schemaFor(endpoint.Payload[0].Type, endpoint.Payload[0].Description, endpoint.Payload[0].Constraints)
+		}
+		op.RequestBody = &RequestBody{Content: map[string]MediaType{"application/json": {Schema: body}}}
+	}
+
+	return op
+}
+
+func buildResponse(endpoint models.Endpoint) Response {
+	response := Response{Description: "Successful response"}
+	if len(endpoint.Response) == 0 {
+		return response
+	}
+
+	body := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, output := range endpoint.Response {
+		body.Properties[output.Name] = schemaFor(output.Type, output.Description, output.Constraints)
+	}
+	response.Content = map[string]MediaType{"application/json": {Schema: body}}
+	return response
+}
+
+// schemaFor maps a model type to a JSON schema, including nested objects and arrays, with
+// the documented constraints the generated validation checks
+func schemaFor(t models.TypeRef, description string, c *models.Constraints) *Schema {
+	schema := &Schema{Description: description}
+
+	switch t.Kind {
+	case models.KindString, models.KindFullname:
+		schema.Type = "string"
+	case models.KindEnum:
+		schema.Type = "string"
+		schema.Enum = t.Values
+	case models.KindInt:
+		schema.Type = "integer"
+	case models.KindFloat, models.KindTimestamp:
+		schema.Type = "number"
+	case models.KindBool:
+		schema.Type = "boolean"
+	case models.KindBoolOrTimestamp:
+		schema.OneOf = []*Schema{{Type: "boolean"}, {Type: "number"}}
+	case models.KindStringOrNumber:
+		schema.OneOf = []*Schema{{Type: "string"}, {Type: "number"}}
+	case models.KindArray:
+		schema.Type = "array"
+		if t.Elem != nil {
+			schema.Items = schemaFor(*t.Elem, "", nil)
+		}
+	case models.KindObject:
+		schema.Type = "object"
+		for _, field := range t.Fields {
+			if schema.Properties == nil {
+				schema.Properties = make(map[string]*Schema)
+			}
+			schema.Properties[field.Name] = schemaFor(field.Type, field.Description, field.Constraints)
+		}
+	}
+
+	if c != nil {
+		switch t.Kind {
+		case models.KindString, models.KindFullname:
+			schema.MinLength = c.MinLength
+			schema.MaxLength = c.MaxLength
+		case models.KindInt, models.KindFloat:
+			schema.Minimum = c.Min
+			schema.Maximum = c.Max
+		}
+	}
+
+	// Anything else, e.g. an undocumented type, is left untyped
+	return schema
+}
+
+// summarize returns the first sentence of a description
+func summarize(description string) string {
+	if description == "" || description == "No description available" {
+		return ""
+	}
+	if i := strings.Index(description, ". "); i != -1 {
+		return description[:i+1]
+	}
+	return description
+}
@@ -0,0 +1,193 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reddit-go-api-generator/models"
+	"reflect"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	endpoints := []models.Endpoint{
+		{
+			ID:                "GET /hot",
+			Method:            "GET",
+			Path:              "/hot",
+			OptionalSubreddit: true,
+			Section:           "listings",
+			Anchor:            "GET_hot",
+			Scopes:            []string{"read"},
+			Description:       "This endpoint is a listing. See below.",
+			QueryParams: []models.Parameter{
+				{Name: "limit", Description: "the maximum number of items desired", Type: models.Primitive(models.KindInt)},
+				{Name: "g", Description: "one of (GLOBAL, US)", Type: models.EnumOf("GLOBAL", "US")},
+			},
+		},
+		{
+			ID:     "POST /api/comment",
+			Method: "POST",
+			Path:   "/api/comment",
+			Scopes: []string{"submit"},
+			Payload: []models.Input{
+				{Name: "text", Description: "raw markdown text", Type: models.Primitive(models.KindString)},
+				{Name: "return_rtjson", Description: "boolean value", Type: models.Primitive(models.KindBool)},
+			},
+		},
+		{
+			ID:     "POST /api/widget",
+			Method: "POST",
+			Path:   "/api/widget",
+			Payload: []models.Input{
+				{Name: "data", Type: models.ArrayOf(models.ObjectOf(
+					models.Field{Name: "height", Description: "an integer", Type: models.Primitive(models.KindInt)},
+				))},
+			},
+		},
+		{
+			ID:       "GET /user/{username}/{where}",
+			Method:   "GET",
+			Path:     "/user/{username}/{where}",
+			Variants: []string{"/user/{username}/comments"},
+		},
+	}
+
+	doc := Export(endpoints)
+
+	if doc.OpenAPI != "3.1.0" || len(doc.Paths) != 6 {
+		t.Fatalf("unexpected document header or paths: %+v", doc)
+	}
+
+	hot := doc.Paths["/r/{subreddit}/hot"].Get
+	if hot == nil {
+		t.Fatal("expected a GET operation for /r/{subreddit}/hot")
+	}
+	if hot.OperationID != "GetHotInSubreddit" || hot.Summary != "This endpoint is a listing." {
+		t.Errorf("unexpected operation id or summary: %q, %q", hot.OperationID, hot.Summary)
+	}
+	if hot.ExternalDocs == nil || hot.ExternalDocs.URL != "https://www.reddit.com/dev/api#GET_hot" {
+		t.Errorf("unexpected external docs %+v", hot.ExternalDocs)
+	}
+	if !reflect.DeepEqual(hot.Security, []map[string][]string{{"oauth2": {"read"}}}) {
+		t.Errorf("unexpected security %v", hot.Security)
+	}
+
+	expectedParams := []Parameter{
+		{Name: "subreddit", In: "path", Required: true, Schema: &Schema{Type: "string"}},
+		{Name: "limit", In: "query", Description: "the maximum number of items desired", Schema: &Schema{Type: "integer"}},
+		{Name: "g", In: "query", Description: "one of (GLOBAL, US)", Schema: &Schema{Type: "string", Enum: []string{"GLOBAL", "US"}}},
+	}
+	if !reflect.DeepEqual(hot.Parameters, expectedParams) {
+		t.Errorf("expected parameters %+v but got %+v", expectedParams, hot.Parameters)
+	}
+
+	frontPage := doc.Paths["/hot"].Get
+	if frontPage == nil || frontPage.OperationID != "GetHot" || !reflect.DeepEqual(frontPage.Parameters, expectedParams[1:]) {
+		t.Errorf("unexpected front page operation %+v", frontPage)
+	}
+
+	variant := doc.Paths["/user/{username}/comments"].Get
+	if variant == nil || variant.OperationID != "GetUserUsernameComments" || len(variant.Parameters) != 1 {
+		t.Errorf("unexpected variant operation %+v", variant)
+	}
+
+	comment := doc.Paths["/api/comment"].Post
+	body := comment.RequestBody.Content["application/json"].Schema
+	if body.Properties["text"].Type != "string" || body.Properties["return_rtjson"].Type != "boolean" {
+		t.Errorf("unexpected request body %+v", body)
+	}
+
+	widget := doc.Paths["/api/widget"].Post.RequestBody.Content["application/json"].Schema
+	if data := widget.Properties["data"]; data.Type != "array" || data.Items.Type != "object" || data.Items.Properties["height"].Type != "integer" {
+		t.Errorf("unexpected nested request body %+v", widget.Properties["data"])
+	}
+
+	scopes := doc.Components.SecuritySchemes["oauth2"].Flows.AuthorizationCode.Scopes
+	if len(scopes) != 2 {
+		t.Errorf("expected the read and submit scopes, got %v", scopes)
+	}
+
+	content, err := doc.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !json.Valid(content) {
+		t.Error("marshalled document is not valid JSON")
+	}
+}
+
+func TestSchemaConstraints(t *testing.T) {
+	one, hundred := 1.0, 100.0
+
+	tests := []struct {
+		name        string
+		t           models.TypeRef
+		constraints *models.Constraints
+		expected    *Schema
+	}{
+		{"text", models.Primitive(models.KindString), &models.Constraints{MaxLength: 300}, &Schema{Type: "string", MaxLength: 300}},
+		{"name", models.Primitive(models.KindFullname), &models.Constraints{MinLength: 3, MaxLength: 20}, &Schema{Type: "string", MinLength: 3, MaxLength: 20}},
+		{"limit", models.Primitive(models.KindInt), &models.Constraints{Min: &one, Max: &hundred}, &Schema{Type: "integer", Minimum: &one, Maximum: &hundred}},
+		{"ratio", models.Primitive(models.KindFloat), &models.Constraints{Max: &one}, &Schema{Type: "number", Maximum: &one}},
+		{"sort", models.EnumOf("hot", "new"), &models.Constraints{MaxLength: 10}, &Schema{Type: "string", Enum: []string{"hot", "new"}}},
+		{"flag", models.Primitive(models.KindBool), &models.Constraints{Min: &one}, &Schema{Type: "boolean"}},
+		{"count", models.Primitive(models.KindInt), nil, &Schema{Type: "integer"}},
+	}
+
+	for _, test := range tests {
+		actual := schemaFor(test.t, "", test.constraints)
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("For input '%s', expected '%+v' but got '%+v'", test.name, test.expected, actual)
+		}
+	}
+
+	doc := Export([]models.Endpoint{{
+		ID:          "GET /hot",
+		Method:      "GET",
+		Path:        "/hot",
+		QueryParams: []models.Parameter{{Name: "limit", Type: models.Primitive(models.KindInt), Constraints: &models.Constraints{Max: &hundred}}},
+		Payload: []models.Input{
+			{Name: "data", Type: models.ObjectOf(models.Field{Name: "title", Type: models.Primitive(models.KindString), Constraints: &models.Constraints{MaxLength: 300}})},
+		},
+	}})
+	hot := doc.Paths["/hot"].Get
+	if limit := hot.Parameters[0].Schema; limit.Maximum == nil || *limit.Maximum != 100 {
+		t.Errorf("expected the limit parameter to have a maximum of 100, got %+v", limit)
+	}
+	if title := hot.RequestBody.Content["application/json"].Schema.Properties["data"].Properties["title"]; title.MaxLength != 300 {
+		t.Errorf("expected the nested title to have a maxLength of 300, got %+v", title)
+	}
+}
+
+func TestUniqueOperationIDs(t *testing.T) {
+	endpoints := []models.Endpoint{
+		{ID: "GET /hot", Method: "GET", Path: "/hot", OptionalSubreddit: true},
+		{ID: "GET /hot/in/subreddit", Method: "GET", Path: "/hot/in/subreddit"},
+		{ID: "GET /new", Method: "GET", Path: "/new", OptionalSubreddit: true},
+	}
+
+	doc := Export(endpoints)
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/hot", "GetHot"},
+		{"/hot/in/subreddit", "GetHotInSubreddit"},
+		{"/r/{subreddit}/hot", "GetHotInSubreddit2"},
+		{"/new", "GetNew"},
+		{"/r/{subreddit}/new", "GetNewInSubreddit"},
+	}
+
+	seen := make(map[string]string)
+	for _, test := range tests {
+		op := doc.Paths[test.path].Get
+		if op == nil || op.OperationID != test.expected {
+			t.Errorf("For input '%s', expected '%s' but got '%+v'", test.path, test.expected, op)
+			continue
+		}
+		if path, ok := seen[op.OperationID]; ok {
+			t.Errorf("Operation id %s is used by both %s and %s", op.OperationID, path, test.path)
+		}
+		seen[op.OperationID] = test.path
+	}
+}
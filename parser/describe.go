@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"reddit-go-api-generator/models"
+)
+
+// Method describes a generated SDK method, for documentation and other tooling
+type Method struct {
+	// Name is the Go method name on ReddiGoSDK
+	Name string
+	// Signature is the method without its receiver, e.g. GetMe() (any, error)
+	Signature string
+	Params    []Param
+	// Result is the first result type, any when the response is not documented
+	Result string
+	// Enums are the enum types generated alongside the method
+	Enums    []models.Enum
+	Endpoint models.Endpoint
+}
+
+// Param is a parameter of a generated method
+type Param struct {
+	// Name is the Go parameter name
+	Name string
+	// Type is the Go type in the signature
+	Type string
+	// In is where the value is sent: path, query or body
+	In string
+	// WireName is the name Reddit expects, e.g. thing_id
+	WireName    string
+	Description string
+	// ModelType is the scraped type
+	ModelType models.TypeRef
+	// Constraints are the documented limits checked before the request is sent
+	Constraints *models.Constraints
+}
+
+// DescribeMethods returns the methods GenerateGoFunctions emits for endpoints, in the same order.
+// Path variants are described as methods of their own.
+func DescribeMethods(endpoints []models.Endpoint) []Method {
+	endpoints = ExpandVariants(endpoints)
+	methods := make([]Method, 0, len(endpoints))
+
+	names := resolveNames(endpoints)
+	for i, endpoint := range endpoints {
+		methods = append(methods, Method{
+			Name:      names[i].Method,
+			Signature: generateMethodSignature(endpoint, names[i]),
+			Params:    collectMethodParams(endpoint, names[i]),
+			Result:    names[i].Response,
+			Enums:     names[i].Enums,
+			Endpoint:  endpoint,
+		})
+	}
+
+	return methods
+}
@@ -0,0 +1,82 @@
+package parser
+
+import (
+	_ "embed"
+	"fmt"
+	"reddit-go-api-generator/models"
+	"strings"
+)
+
+//go:embed reddigotest_helpers.txt
+var fakeServerHelpers string
+
+//go:embed recorder_helpers.txt
+var recorderHelpers string
+
+// FakeServerPackageName returns the name of the generated test package, e.g. reddigotest
+func FakeServerPackageName(opts Options) string {
+	return opts.packageName() + "test"
+}
+
+// generateFakeServer renders the test package: an httptest based fake with one route per endpoint
+func generateFakeServer(endpoints []models.Endpoint, opts Options) string {
+	packageName := FakeServerPackageName(opts)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n", GeneratedHeader)
+	fmt.Fprintf(&b, "// Package %s provides a fake Reddit API server for testing code built on package %s.\n", packageName, opts.packageName())
+	fmt.Fprintf(&b, "// It answers every generated endpoint, records the calls it receives and reports\n")
+	fmt.Fprintf(&b, "// requests that do not match the documented paths and parameters.\n")
+	fmt.Fprintf(&b, "package %s\n", packageName)
+	b.WriteString(fakeServerHelpers)
+
+	b.WriteString("\n// routes lists every generated endpoint\nvar routes = []Route{\n")
+	endpoints = ExpandVariants(endpoints)
+	for i, name := range FunctionNames(endpoints) {
+		b.WriteString(generateRoute(endpoints[i], name))
+	}
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// generateRecorder renders the record and replay transport of the test package
+func generateRecorder(opts Options) string {
+	return fmt.Sprintf("%s\n\npackage %s\n%s", GeneratedHeader, FakeServerPackageName(opts), recorderHelpers)
+}
+
+// generateRoute renders the Route literals for an endpoint, one per path it can be called on
+func generateRoute(endpoint models.Endpoint, name string) string {
+	var queryParams []string
+	for _, queryParam := range endpoint.QueryParams {
+		queryParams = append(queryParams, toSnakeCase(queryParam.Name))
+	}
+
+	// A single json payload is sent as the whole body, so its keys are unknown
+	var bodyParams []string
+	if !(len(endpoint.Payload) == 1 && strings.ToLower(endpoint.Payload[0].Name) == "json") {
+		for _, payload := range endpoint.Payload {
+			bodyParams = append(bodyParams, wireName(payload.Name))
+		}
+	}
+
+	var routes string
+	for _, path := range CallablePaths(endpoint) {
+		routes += fmt.Sprintf("\t{Name: %q, Method: %q, Path: %q, QueryParams: %s, BodyParams: %s},\n",
+			name, endpoint.Method, path, stringSliceLiteral(queryParams), stringSliceLiteral(bodyParams))
+	}
+	return routes
+}
+
+// stringSliceLiteral renders values as a Go []string literal
+func stringSliceLiteral(values []string) string {
+	if len(values) == 0 {
+		return "nil"
+	}
+
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+	return "[]string{" + strings.Join(quoted, ", ") + "}"
+}
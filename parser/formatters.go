@@ -2,14 +2,6 @@ package parser
 
 import "strings"
 
-// replacePeriodsWithDot replaces all periods in the path with the word "dot".
-func replacePeriodsWithDot(path string) string {
-	return strings.ReplaceAll(path, ".", "Dot")
-}
-func cleanPath(path string) string {
-	return ensureCamelCase(replacePeriodsWithDot(removeDynamicFields(path)))
-}
-
 func splitAndTakeFirst(input string) string {
 	// Check if there is a slash in the string
 	if idx := strings.Index(input, "/"); idx != -1 {
@@ -30,56 +22,18 @@ func RemoveInvalidCharacters(property string) string {
 	return newStr
 }
 
+// formatProperty turns a scraped field name into a Go parameter name
 func formatProperty(property string) string {
-	return ensureNonKeyword(toLowerCamelCase(RemoveInvalidCharacters(property)))
-}
-
-func toCamelCaseFromSlash(str string) string {
-	parts := strings.Split(str, "/")
-	for i, part := range parts {
-		parts[i] = strings.Title(part)
-	}
-
-	return strings.Join(parts, "")
-}
-
-func toCamelCaseFromSnakeCase(str string) string {
-	parts := strings.Split(str, "_")
-	for i, part := range parts {
-		parts[i] = strings.Title(part)
-	}
-
-	return strings.Join(parts, "")
-}
-
-func toCamelCaseFromKebabCase(str string) string {
-	parts := strings.Split(str, "-")
-	for i, part := range parts {
-		parts[i] = strings.Title(part)
-	}
-
-	return strings.Join(parts, "")
-}
-
-func ensureCamelCase(str string) string {
-	return toCamelCaseFromKebabCase(toCamelCaseFromSnakeCase(toCamelCaseFromSlash(str)))
-}
-
-// Converts a string to lowerCamelCase
-func toLowerCamelCase(str string) string {
-	camel := ensureCamelCase(str)
-	return strings.ToLower(string(camel[0])) + camel[1:]
-}
-
-func ensureNonKeyword(str string) string {
-	if str == "type" {
-		str = "typeValue"
-	}
-
-	return str
+	return unexportedName(RemoveInvalidCharacters(property))
 }
 
 // Converts a string to snake_case
 func toSnakeCase(str string) string {
 	return strings.ReplaceAll(strings.ToLower(RemoveInvalidCharacters(str)), " ", "_")
 }
+
+// wireName is the key a payload field is sent with. Unlike toSnakeCase it keeps the case of
+// names taken from JSON models, such as shortName.
+func wireName(str string) string {
+	return strings.ReplaceAll(RemoveInvalidCharacters(str), " ", "_")
+}
@@ -2,27 +2,35 @@ package parser
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"reddit-go-api-generator/models"
 	"strings"
 )
 
-// Helper function to remove dynamic fields from URLs for function and type names
-func removeDynamicFields(path string) string {
-	// Replace placeholders like [subreddit] and {where}
-	cleanPath := strings.ReplaceAll(path, "[", "")
-	cleanPath = strings.ReplaceAll(cleanPath, "]", "")
-	cleanPath = strings.ReplaceAll(cleanPath, "{", "")
-	cleanPath = strings.ReplaceAll(cleanPath, "}", "")
-	return cleanPath
+// FunctionNames returns the name of the SDK method generated for each endpoint of
+// ExpandVariants(endpoints), in order
+func FunctionNames(endpoints []models.Endpoint) []string {
+	endpoints = ExpandVariants(endpoints)
+	names := make([]string, len(endpoints))
+	for i, endpointNames := range resolveNames(endpoints) {
+		names[i] = endpointNames.Method
+	}
+	return names
 }
 
-// Helper function to create the function name in camel case, handling placeholders
+// Helper function to create the preferred method name, e.g. GET /api/v1/me becomes GetMe
 func buildFunctionName(endpoint models.Endpoint) string {
-	method := strings.Title(strings.ToLower(endpoint.Method))
-	name := cleanAPIPath(endpoint.Path)
-	name = cleanPath(name)
-	return fmt.Sprintf("%s%s", method, name)
+	return methodName(endpoint.Method, cleanAPIPath(endpoint.Path))
+}
+
+// Helper function to create the method name used when the preferred one is taken, keeping
+// the /api/ and /api/v1/ prefixes, e.g. GetAPIV1Me
+func buildFullFunctionName(endpoint models.Endpoint) string {
+	return methodName(endpoint.Method, endpoint.Path)
+}
+
+func methodName(method, path string) string {
+	return exportedName(strings.ToLower(method) + " " + path)
 }
 
 // Helper function to safely remove /api/v1/ or /api/ from the path
@@ -35,22 +43,25 @@ func cleanAPIPath(path string) string {
 }
 
 // Helper function to generate the response struct if needed
-func generateResponseStruct(endpoint models.Endpoint, funcName string) string {
+func generateResponseStruct(endpoint models.Endpoint, names endpointNames) string {
 	if len(endpoint.Response) == 0 {
 		return ""
 	}
-	responseStructName := getResponseStructName(funcName, endpoint.Response)
+	responseStructName := names.Response
 
 	structDef := fmt.Sprintf("// %s represents the response for %s %s\n", responseStructName, endpoint.Method, endpoint.Path)
 	structDef += fmt.Sprintf("type %s struct {\n", responseStructName)
+	// Nested objects are looked up by their path like payload objects
+	responseNames := endpointNames{Requests: names.Responses}
+	fields := newNamespace()
 	for _, resp := range endpoint.Response {
-		fieldName := strings.Title(toCamelCaseFromSnakeCase(resp.Name))
-
-		if fieldName == "Type" {
-			fieldName = "TypeValue" // Avoid Go keyword conflict
+		fieldName := exportedName(RemoveInvalidCharacters(resp.Name))
+		if fieldName == "" {
+			fieldName = "Field"
 		}
+		fieldName = fields.claim(fieldName)
 
-		fieldType := adjustEnumType(resp.Type)
+		fieldType := goType(resp.Type, resp.Name, responseNames)
 		jsonTag := toSnakeCase(resp.Name)
 
 		// Format the description as a multi-line comment if it contains multiple lines
@@ -59,12 +70,33 @@ func generateResponseStruct(endpoint models.Endpoint, funcName string) string {
 		structDef += fmt.Sprintf("\t%s %s `json:\"%s\"` %s\n", fieldName, fieldType, jsonTag, description)
 	}
 	structDef += "}\n\n"
+
+	walkObjects(endpoint.Response, func(path string, t models.TypeRef) {
+		structName := names.Responses[path]
+
+		structDef += fmt.Sprintf("// %s is the %s field of the %s %s response\n", structName, path, endpoint.Method, endpoint.Path)
+		structDef += fmt.Sprintf("type %s struct {\n", structName)
+		fields := newNamespace()
+		for _, field := range t.Fields {
+			fieldName := exportedName(RemoveInvalidCharacters(field.Name))
+			if fieldName == "" {
+				fieldName = "Field"
+			}
+			fieldName = fields.claim(fieldName)
+
+			fieldType := goType(field.Type, path+"."+field.Name, responseNames)
+			description := formatFieldDescription(field.Description)
+
+			structDef += fmt.Sprintf("\t%s %s `json:\"%s\"` %s\n", fieldName, fieldType, field.Name, description)
+		}
+		structDef += "}\n\n"
+	})
 	return structDef
 }
 
 func getResponseStructName(funcName string, response []models.Output) string {
 	if len(response) == 0 {
-		println(fmt.Sprintf("%s has no response body", funcName))
+		slog.Debug("Endpoint has no response body", "function", funcName)
 		return "any"
 	}
 
@@ -98,58 +130,78 @@ Description: %s
 `, funcName, endpoint.Method, endpoint.Path, endpoint.ID, endpoint.Description)
 }
 
-func generateFunctionSignature(endpoint models.Endpoint, funcName string, enums []models.Enum) string {
-	log.Printf("Generating function signature for: %s", funcName)
+func generateFunctionSignature(endpoint models.Endpoint, names endpointNames) string {
+	slog.Debug("Generating function signature", "function", names.Method)
 
-	params := collectFunctionParameters(endpoint)
+	return fmt.Sprintf("func (sdk *ReddiGoSDK) %s {\n", generateMethodSignature(endpoint, names))
+}
 
-	log.Printf("Parameters collected: %v", params)
+// Helper function to render a method name, parameters and results without the receiver,
+// shared by the method itself and the generated interfaces
+func generateMethodSignature(endpoint models.Endpoint, names endpointNames) string {
+	params := collectFunctionParameters(endpoint, names)
 
-	responseStructName := getResponseStructName(funcName, endpoint.Response)
+	slog.Debug("Parameters collected", "function", names.Method, "params", params)
 
-	return fmt.Sprintf("func (sdk *ReddiGoSDK) %s(%s) (%s, error) {\n", funcName, strings.Join(params, ", "), responseStructName)
+	return fmt.Sprintf("%s(%s) (%s, error)", names.Method, strings.Join(params, ", "), names.Response)
 }
 
 // Helper function to collect parameters for the function signature
-func collectFunctionParameters(endpoint models.Endpoint) []string {
+func collectFunctionParameters(endpoint models.Endpoint, names endpointNames) []string {
 	var params []string
+	for _, param := range collectMethodParams(endpoint, names) {
+		params = append(params, fmt.Sprintf("%s %s", param.Name, param.Type))
+	}
+
+	return params
+}
+
+// Helper function to collect the parameters of the generated method, in signature order
+func collectMethodParams(endpoint models.Endpoint, names endpointNames) []Param {
+	params, _ := methodParams(endpoint, names)
+	return params
+}
+
+// methodParams returns the parameters of the generated method and the payload and query
+// parameters left out because an earlier parameter already has their Go name
+func methodParams(endpoint models.Endpoint, names endpointNames) ([]Param, []Param) {
+	var params, dropped []Param
 	paramSet := make(map[string]bool) // A set to track existing parameter names
 
+	add := func(param Param) {
+		if !paramSet[param.Name] { // Only add if it hasn't been added yet
+			params = append(params, param)
+			paramSet[param.Name] = true
+		} else if param.In != "path" {
+			// Path placeholders are listed both in the path and in URLParams
+			dropped = append(dropped, param)
+		}
+	}
+
+	// An optional [/r/subreddit] prefix comes first, as it does in the path
+	if endpoint.OptionalSubreddit {
+		add(Param{Name: formatProperty(subredditParam), Type: "string", In: "path", WireName: subredditParam,
+			Description: "Optional subreddit to scope the request to, empty for the whole site"})
+	}
+
 	// Add dynamic parts (e.g., subreddit, where)
 	dynamicFields := extractDynamicFields(endpoint.Path)
 	for _, field := range dynamicFields {
-		paramName := formatProperty(field)
-		if !paramSet[paramName] { // Only add if it hasn't been added yet
-			params = append(params, fmt.Sprintf("%s string", paramName))
-			paramSet[paramName] = true
-		}
+		add(Param{Name: formatProperty(field), Type: "string", In: "path", WireName: field})
 	}
 
 	// Add URL parameters, payload, and query parameters
 	for _, param := range endpoint.URLParams {
-		paramName := formatProperty(param)
-		if !paramSet[paramName] {
-			params = append(params, fmt.Sprintf("%s string", paramName))
-			paramSet[paramName] = true
-		}
+		add(Param{Name: formatProperty(param), Type: "string", In: "path", WireName: param})
 	}
 	for _, payload := range endpoint.Payload {
-		paramName := formatProperty(payload.Name)
-		paramType := adjustEnumType(payload.Type)
-		if !paramSet[paramName] {
-			params = append(params, fmt.Sprintf("%s %s", paramName, paramType))
-			paramSet[paramName] = true
-		}
+		add(Param{Name: formatProperty(payload.Name), Type: goType(payload.Type, payload.Name, names), In: "body", WireName: wireName(payload.Name), Description: payload.Description, ModelType: payload.Type, Constraints: payload.Constraints})
 	}
 	for _, queryParam := range endpoint.QueryParams {
-		paramName := formatProperty(queryParam.Name)
-		if !paramSet[paramName] {
-			params = append(params, fmt.Sprintf("%s string", paramName))
-			paramSet[paramName] = true
-		}
+		add(Param{Name: formatProperty(queryParam.Name), Type: queryParamType(queryParam, names), In: "query", WireName: toSnakeCase(queryParam.Name), Description: queryParam.Description, ModelType: queryParam.Type, Constraints: queryParam.Constraints})
 	}
 
-	return params
+	return params, dropped
 }
 
 // Helper function to build the URL using dynamic fields and parameters
@@ -164,7 +216,7 @@ func buildURL(endpoint models.Endpoint) string {
 		urlBuild = fmt.Sprintf("\treqUrl := fmt.Sprintf(\"%s\"", urlPattern)
 
 		for _, field := range dynamicFields {
-			urlBuild += fmt.Sprintf(", %s", toLowerCamelCase(field))
+			urlBuild += fmt.Sprintf(", %s", formatProperty(field))
 		}
 		urlBuild += ")"
 	} else {
@@ -172,6 +224,11 @@ func buildURL(endpoint models.Endpoint) string {
 	}
 
 	urlBuild += "\n"
+
+	if endpoint.OptionalSubreddit {
+		subreddit := formatProperty(subredditParam)
+		urlBuild += fmt.Sprintf("\tif %s != \"\" {\n\t\treqUrl = \"/r/\" + %s + reqUrl\n\t}\n", subreddit, subreddit)
+	}
 	return urlBuild
 }
 
@@ -212,7 +269,7 @@ func buildPayload(endpoint models.Endpoint) string {
 
 	// Check if the payload should be treated as the entire JSON body
 	if len(endpoint.Payload) == 1 && strings.ToLower(endpoint.Payload[0].Name) == "json" {
-		payloadName := toLowerCamelCase(endpoint.Payload[0].Name)
+		payloadName := formatProperty(endpoint.Payload[0].Name)
 
 		return fmt.Sprintf("\tpayload := %s\n", payloadName)
 	}
@@ -222,7 +279,7 @@ func buildPayload(endpoint models.Endpoint) string {
 		//payloadBuild += fmt.Sprintf("\t\t\"%s\": %s,\n", toSnakeCase(payload.Name), toLowerCamelCase(payload.Name))
 
 		paramName := formatProperty(payload.Name)
-		jsonName := toSnakeCase(payload.Name)
+		jsonName := wireName(payload.Name)
 
 		payloadBuild += fmt.Sprintf("\t\t\"%s\": %s,\n", jsonName, paramName)
 	}
@@ -232,44 +289,51 @@ func buildPayload(endpoint models.Endpoint) string {
 }
 
 // Helper function to build query parameters
-func buildQueryParams(endpoint models.Endpoint) string {
+func buildQueryParams(endpoint models.Endpoint, names endpointNames) string {
 	if len(endpoint.QueryParams) == 0 {
 		return ""
 	}
+	// Query parameters are optional, empty strings, false and nil are left out of the URL
 	queryParamsBuild := "\tqueryParams := urlpkg.Values{}\n"
 	for _, queryParam := range endpoint.QueryParams {
-		queryParamsBuild += fmt.Sprintf("\tqueryParams.Add(\"%s\", %s)\n", toSnakeCase(queryParam.Name), toLowerCamelCase(queryParam.Name))
+		paramName := formatProperty(queryParam.Name)
+		condition, value := paramName+` != ""`, paramName
+		switch paramType := queryParamType(queryParam, names); paramType {
+		case "string":
+		case "*int", "*float64":
+			condition, value = paramName+" != nil", fmt.Sprintf("queryValue(*%s)", paramName)
+		case "bool":
+			condition, value = paramName, fmt.Sprintf("queryValue(%s)", paramName)
+		default:
+			// Fullnames and enums are string types
+			value = fmt.Sprintf("string(%s)", paramName)
+		}
+		queryParamsBuild += fmt.Sprintf("\tif %s {\n\t\tqueryParams.Add(\"%s\", %s)\n\t}\n", condition, toSnakeCase(queryParam.Name), value)
 	}
-	queryParamsBuild += "\treqUrl += \"?\" + queryParams.Encode()\n"
+	queryParamsBuild += "\tif len(queryParams) > 0 {\n\t\treqUrl += \"?\" + queryParams.Encode()\n\t}\n"
 	return queryParamsBuild
 }
 
 // Helper function to construct the request using MakeRequest from ReddiGoSDK
-func buildRequest(endpoint models.Endpoint, funcName string) string {
+func buildRequest(endpoint models.Endpoint, names endpointNames) string {
 	requestBuild := fmt.Sprintf("\t// Construct the request for %s method\n", endpoint.Method)
 
 	// Build the URL using the helper function
 	// requestBuild += buildURL(endpoint)
 
-	responseName := getResponseStructName(funcName, endpoint.Response)
-	newInstanceOfResponseStr := ""
-
-	if responseName == "any" {
-		newInstanceOfResponseStr = "nil"
-	} else {
-		newInstanceOfResponseStr = fmt.Sprintf("%s{}", responseName)
-	}
+	responseName := names.Response
+	newInstanceOfResponseStr := zeroResponse(names)
 
 	// Variable to hold the body for the MakeRequest function
 	bodyVar := "nil"
 
 	// Add headers and body for POST/PUT methods
-	if endpoint.Method == "POST" || endpoint.Method == "PATCH" || endpoint.Method == "PUT" {
-		requestBuild += "\tjsonPayload, err := jsonpkg.Marshal(payload)\n"
+	if sendsJSONBody(endpoint.Method) {
+		requestBuild += "\tjsonPayload, err := jsonBody(payload)\n"
 		requestBuild += "\tif err != nil {\n\t\treturn " + newInstanceOfResponseStr + ", err\n\t}\n"
 		//requestBuild += "\treq.Header.Set(\"Content-Type\", \"application/json\")\n"
 		//requestBuild += "\treq.Body = io.NopCloser(bytes.NewBuffer(jsonPayload))\n"
-		bodyVar = "bytes.NewBuffer(jsonPayload)"
+		bodyVar = "jsonPayload"
 	}
 
 	requestBuild += fmt.Sprintf("\tresp, err := sdk.MakeRequest(\"%s\", reqUrl, %s)\n", endpoint.Method, bodyVar)
@@ -282,6 +346,14 @@ func buildRequest(endpoint models.Endpoint, funcName string) string {
 	return requestBuild
 }
 
+// zeroResponse is the first result returned along with an error
+func zeroResponse(names endpointNames) string {
+	if names.Response == "any" {
+		return "nil"
+	}
+	return fmt.Sprintf("%s{}", names.Response)
+}
+
 // Helper function to close the function with a return statement
 func buildFunctionEnd(funcName string) string {
 	return fmt.Sprintf("\treturn response, nil\n}\n\n")
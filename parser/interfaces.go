@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"fmt"
+	"reddit-go-api-generator/models"
+	"strings"
+)
+
+// runtimeMethodSignatures are the methods the runtime helpers add to ReddiGoSDK, the
+// signatures of reservedMethodNames
+var runtimeMethodSignatures = []string{
+	"MakeRequest(method, endpoint string, body io.Reader) (*http.Response, error)",
+	"SubmitMedia(upload MediaUpload, post MediaPost) (MediaAsset, any, error)",
+	"UploadMedia(upload MediaUpload) (MediaAsset, error)",
+}
+
+// sectionInterface groups the methods generated for one documentation section
+type sectionInterface struct {
+	Name      string
+	Section   string
+	Methods   []string
+	Endpoints []models.Endpoint
+}
+
+// SectionInterfaceName returns the name of the interface generated for a documentation
+// section, e.g. "links & comments" becomes LinksAndCommentsAPI
+func SectionInterfaceName(section string) string {
+	name := exportedName(strings.ReplaceAll(section, "&", " and "))
+	if name == "" {
+		return ""
+	}
+	return name + "API"
+}
+
+// groupBySection collects method signatures per section in order of first appearance.
+// Sections are told apart by title, so two titles with the same interface name get
+// interfaces of their own. Endpoints without a section are returned separately.
+func groupBySection(endpoints []models.Endpoint) ([]*sectionInterface, []string) {
+	var sections []*sectionInterface
+	byName := make(map[string]*sectionInterface)
+	var unsectioned []string
+
+	endpoints = ExpandVariants(endpoints)
+	names := resolveNames(endpoints)
+	interfaceNames, _ := sectionInterfaceNames(endpoints)
+	for i, endpoint := range endpoints {
+		signature := generateMethodSignature(endpoint, names[i])
+
+		name, ok := interfaceNames[endpoint.Section]
+		if !ok {
+			unsectioned = append(unsectioned, signature)
+			continue
+		}
+
+		section, ok := byName[name]
+		if !ok {
+			section = &sectionInterface{Name: name, Section: endpoint.Section}
+			byName[name] = section
+			sections = append(sections, section)
+		}
+		section.Methods = append(section.Methods, signature)
+		section.Endpoints = append(section.Endpoints, endpoint)
+	}
+
+	return sections, unsectioned
+}
+
+// generateInterfaces renders an interface per documentation section and an API interface
+// embedding all of them, so callers can depend on an interface instead of *ReddiGoSDK
+func generateInterfaces(endpoints []models.Endpoint, opts Options) string {
+	sections, unsectioned := groupBySection(endpoints)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\npackage %s\n\n", GeneratedHeader, opts.packageName())
+	b.WriteString("import (\n\t\"io\"\n\t\"net/http\"\n)\n\n")
+
+	for _, section := range sections {
+		fmt.Fprintf(&b, "// %s lists the methods documented in the %q section of the Reddit API\n", section.Name, section.Section)
+		fmt.Fprintf(&b, "type %s interface {\n", section.Name)
+		for _, method := range section.Methods {
+			fmt.Fprintf(&b, "\t%s\n", method)
+		}
+		b.WriteString("}\n\n")
+	}
+
+	b.WriteString("// API lists every method of ReddiGoSDK. Depend on it (or one of the per-section\n")
+	b.WriteString("// interfaces) to swap in fakes or decorators.\n")
+	b.WriteString("type API interface {\n")
+	for _, section := range sections {
+		fmt.Fprintf(&b, "\t%s\n", section.Name)
+	}
+	if len(sections) > 0 && len(unsectioned) > 0 {
+		b.WriteString("\n")
+	}
+	for _, method := range unsectioned {
+		fmt.Fprintf(&b, "\t%s\n", method)
+	}
+	if len(sections) > 0 || len(unsectioned) > 0 {
+		b.WriteString("\n")
+	}
+	for _, method := range runtimeMethodSignatures {
+		fmt.Fprintf(&b, "\t%s\n", method)
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("// Make sure the client keeps satisfying every generated interface\n")
+	b.WriteString("var _ API = (*ReddiGoSDK)(nil)\n")
+
+	return b.String()
+}
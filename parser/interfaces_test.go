@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"reddit-go-api-generator/models"
+	"strings"
+	"testing"
+)
+
+func TestSectionInterfaceName(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"account", "AccountAPI"},
+		{"links & comments", "LinksAndCommentsAPI"},
+		{"new modmail", "NewModmailAPI"},
+		{"(beta)", "BetaAPI"},
+		{"2fa", "X2faAPI"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		output := SectionInterfaceName(test.input)
+		if output != test.expected {
+			t.Errorf("For input '%s', expected '%s' but got '%s'", test.input, test.expected, output)
+		}
+	}
+}
+
+func TestGenerateInterfacesListsRuntimeMethods(t *testing.T) {
+	output := generateInterfaces([]models.Endpoint{
+		{ID: "GET /api/v1/me", Method: "GET", Path: "/api/v1/me", Section: "account"},
+	}, Options{})
+
+	api := output[strings.Index(output, "type API interface {"):]
+	for _, expected := range []string{
+		"\tAccountAPI\n",
+		"\tMakeRequest(method, endpoint string, body io.Reader) (*http.Response, error)\n",
+		"\tSubmitMedia(upload MediaUpload, post MediaPost) (MediaAsset, any, error)\n",
+		"\tUploadMedia(upload MediaUpload) (MediaAsset, error)\n",
+	} {
+		if !strings.Contains(api, expected) {
+			t.Errorf("Expected '%s' in '%s'", expected, api)
+		}
+	}
+}
+
+func TestGenerateInterfacesKeepsCollidingSectionsApart(t *testing.T) {
+	output := generateInterfaces([]models.Endpoint{
+		{ID: "GET /api/info", Method: "GET", Path: "/api/info", Section: "links & comments"},
+		{ID: "POST /api/comment", Method: "POST", Path: "/api/comment", Section: "links and comments"},
+	}, Options{})
+
+	for _, expected := range []string{
+		"type LinksAndCommentsAPI interface {\n\tGetInfo() (any, error)\n}\n",
+		"type LinksAndCommentsAPI2 interface {\n\tPostComment() (any, error)\n}\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected '%s' in '%s'", expected, output)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package parser
+
+import (
+	"fmt"
+	"reddit-go-api-generator/models"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// goKeywords can never be used as identifiers
+var goKeywords = stringSet(
+	"break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough",
+	"for", "func", "go", "goto", "if", "import", "interface", "map", "package", "range",
+	"return", "select", "struct", "switch", "type", "var",
+)
+
+// predeclaredIdentifiers may legally be shadowed, but a parameter named string or len
+// breaks the generated code that still needs the original
+var predeclaredIdentifiers = stringSet(
+	"any", "append", "bool", "byte", "cap", "clear", "close", "comparable", "complex",
+	"complex64", "complex128", "copy", "delete", "error", "false", "float32", "float64",
+	"imag", "int", "int8", "int16", "int32", "int64", "iota", "len", "make", "max", "min",
+	"new", "nil", "panic", "print", "println", "real", "recover", "rune", "string", "true",
+	"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+)
+
+// generatedLocals are the receiver, variables and imports used inside generated method bodies
+var generatedLocals = stringSet(
+	"sdk", "reqUrl", "payload", "queryParams", "jsonPayload", "jsonBody", "queryValue", "resp",
+	"response", "err", "errs", "fmt", "http", "io", "jsonpkg", "strings", "time", "urlpkg",
+)
+
+// reservedMethodNames are methods of ReddiGoSDK written by hand in the runtime helpers
+var reservedMethodNames = []string{"MakeRequest", "SubmitMedia", "UploadMedia"}
+
+// reservedTypeNames are package level identifiers declared by interfaces.go and the embedded
+// runtime helpers
+var reservedTypeNames = []string{
+	"API", "DefaultBaseURL", "NewReddiGoSDK", "Ptr", "ReddiGoSDK", "RedditConfig",
+	"Doer", "DoerFunc", "ErrReadOnly", "Middleware",
+	"Fullname", "KindAccount", "KindAward", "KindComment", "KindLink", "KindMessage", "KindSubreddit",
+	"NewFullname", "ParseFullname", "ThingKind",
+	"BoolOrTimestamp", "StringOrNumber", "Timestamp",
+	"ValidationError", "ValidationErrors",
+	"MediaAsset", "MediaPost", "MediaUpload",
+}
+
+// commonInitialisms are written in upper case, as golint expects
+var commonInitialisms = stringSet(
+	"ACL", "API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML", "HTTP", "HTTPS", "ID",
+	"IP", "JSON", "LHS", "QPS", "RAM", "RHS", "RPC", "SLA", "SMTP", "SQL", "SSH", "TCP", "TLS",
+	"TTL", "UDP", "UI", "UID", "UUID", "URI", "URL", "UTF8", "VM", "XML", "XMPP", "XSRF", "XSS",
+)
+
+func stringSet(values ...string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
+
+// splitWords breaks s into words at every character that cannot appear in an identifier
+// and at camelCase boundaries, e.g. "conversation_id", "asset.json" and "thingId"
+func splitWords(s string) []string {
+	var words []string
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(field)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			lowerToUpper := unicode.IsUpper(runes[i]) && !unicode.IsUpper(runes[i-1])
+			// Split HTTPServer into HTTP and Server
+			acronymEnd := unicode.IsUpper(runes[i]) && unicode.IsUpper(runes[i-1]) &&
+				i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if lowerToUpper || acronymEnd {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// capitalize upper-cases initialisms, including plurals such as IDs, and the first letter of
+// any other word, leaving the rest of it untouched
+func capitalize(word string) string {
+	upper := strings.ToUpper(word)
+	if commonInitialisms[upper] {
+		return upper
+	}
+	if len(upper) > 2 && strings.HasSuffix(upper, "S") && commonInitialisms[upper[:len(upper)-1]] {
+		return upper[:len(upper)-1] + "s"
+	}
+
+	runes := []rune(word)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// exportedName turns s into an exported identifier, e.g. "conversation_id" becomes ConversationID.
+// It returns an empty string when s has no letters or digits.
+func exportedName(s string) string {
+	name := joinWords(splitWords(s))
+	if name != "" && unicode.IsDigit(rune(name[0])) {
+		name = "X" + name
+	}
+	return name
+}
+
+// joinWords capitalizes and concatenates words
+func joinWords(words []string) string {
+	var b strings.Builder
+	for _, word := range words {
+		b.WriteString(capitalize(word))
+	}
+	return b.String()
+}
+
+// unexportedName turns s into a parameter name that is a valid identifier and does not shadow
+// anything the generated code relies on, e.g. "thing_id" becomes thingID and "type" typeValue
+func unexportedName(s string) string {
+	words := splitWords(s)
+	if len(words) == 0 {
+		return "value"
+	}
+
+	name := strings.ToLower(words[0]) + joinWords(words[1:])
+	if unicode.IsDigit(rune(name[0])) {
+		name = "x" + name
+	}
+	if goKeywords[name] || predeclaredIdentifiers[name] || generatedLocals[name] {
+		name += "Value"
+	}
+	return name
+}
+
+// namespace hands out identifiers that are unique within one Go scope
+type namespace struct {
+	taken map[string]bool
+}
+
+func newNamespace(reserved ...string) *namespace {
+	return &namespace{taken: stringSet(reserved...)}
+}
+
+// claim takes the first free candidate. When every candidate is taken the last one gets the
+// smallest free numeric suffix, so the outcome only depends on the order of the claims.
+func (n *namespace) claim(candidates ...string) string {
+	for _, candidate := range candidates {
+		if !n.taken[candidate] {
+			n.taken[candidate] = true
+			return candidate
+		}
+	}
+
+	last := candidates[len(candidates)-1]
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s%d", last, i)
+		if !n.taken[candidate] {
+			n.taken[candidate] = true
+			return candidate
+		}
+	}
+}
+
+// endpointNames are the identifiers declared for one endpoint
+type endpointNames struct {
+	Method string
+	// Response is the response struct, any when the response is not documented
+	Response string
+	Enums    []models.Enum
+	// Request is the type holding the arguments of a method with constrained arguments, e.g.
+	// GetHotRequest, empty for the other methods
+	Request string
+	// Requests names the structs of nested payload objects by their path, e.g. styles or data[]
+	Requests map[string]string
+	// Responses names the structs of nested response objects by their path, e.g. data.children[]
+	Responses map[string]string
+	// QueryEnums names the enum type of each enum query parameter by the parameter name
+	QueryEnums map[string]string
+	// Renamed lists the identifiers that could not take their preferred name
+	Renamed []renamedIdentifier
+}
+
+// renamedIdentifier is an identifier declared under another name than the one it prefers
+type renamedIdentifier struct {
+	// Field is what the identifier was generated for, empty for the method
+	Field     string
+	Preferred string
+	Name      string
+}
+
+// sectionInterfaceNames assigns every documentation section a unique interface name, by
+// section title. Sections claim names sorted by title, so the outcome does not depend on the
+// page order. Sections whose preferred name is taken, e.g. "links and comments" after
+// "links & comments", are listed in the renamed identifiers.
+func sectionInterfaceNames(endpoints []models.Endpoint) (map[string]string, []renamedIdentifier) {
+	var sections []string
+	names := make(map[string]string)
+	for _, endpoint := range endpoints {
+		if _, ok := names[endpoint.Section]; !ok && SectionInterfaceName(endpoint.Section) != "" {
+			names[endpoint.Section] = ""
+			sections = append(sections, endpoint.Section)
+		}
+	}
+	sort.Strings(sections)
+
+	var renamed []renamedIdentifier
+	interfaces := newNamespace(reservedTypeNames...)
+	for _, section := range sections {
+		preferred := SectionInterfaceName(section)
+		names[section] = interfaces.claim(preferred)
+		if names[section] != preferred {
+			renamed = append(renamed, renamedIdentifier{Field: "section " + section, Preferred: preferred, Name: names[section]})
+		}
+	}
+	return names, renamed
+}
+
+// resolveNames assigns every method, response struct, enum type and enum constant a unique
+// name. Endpoints claim names sorted by method and path, so an endpoint keeps its names
+// wherever it appears on the documentation page. The result is indexed like endpoints.
+func resolveNames(endpoints []models.Endpoint) []endpointNames {
+	order := make([]int, len(endpoints))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ea, eb := endpoints[order[a]], endpoints[order[b]]
+		if ea.Method != eb.Method {
+			return ea.Method < eb.Method
+		}
+		return ea.Path < eb.Path
+	})
+
+	names := make([]endpointNames, len(endpoints))
+
+	// claim records the identifiers that lose their first candidate to another one
+	claim := func(i int, ns *namespace, field string, candidates ...string) string {
+		name := ns.claim(candidates...)
+		if name != candidates[0] {
+			names[i].Renamed = append(names[i].Renamed, renamedIdentifier{Field: field, Preferred: candidates[0], Name: name})
+		}
+		return name
+	}
+
+	// Methods first, so a type can never push a method off its preferred name
+	methods := newNamespace(reservedMethodNames...)
+	for _, i := range order {
+		names[i].Method = claim(i, methods, "", buildFunctionName(endpoints[i]), buildFullFunctionName(endpoints[i]))
+	}
+
+	types := newNamespace(reservedTypeNames...)
+	sections, _ := sectionInterfaceNames(endpoints)
+	for _, name := range sections {
+		types.taken[name] = true
+	}
+
+	for _, i := range order {
+		endpoint := endpoints[i]
+
+		names[i].Response = getResponseStructName(names[i].Method, endpoint.Response)
+		if names[i].Response != "any" {
+			names[i].Response = claim(i, types, "response", names[i].Response)
+		}
+
+		walkObjects(endpoint.Payload, func(path string, _ models.TypeRef) {
+			if names[i].Requests == nil {
+				names[i].Requests = make(map[string]string)
+			}
+			names[i].Requests[path] = claim(i, types, path, objectStructName(names[i].Method, path))
+		})
+
+		walkObjects(endpoint.Response, func(path string, _ models.TypeRef) {
+			if names[i].Responses == nil {
+				names[i].Responses = make(map[string]string)
+			}
+			names[i].Responses[path] = claim(i, types, "response "+path, objectStructName(names[i].Response, path))
+		})
+
+		for _, field := range collectEnums(endpoint, names[i].Method) {
+			enum := field.Enum
+			enum.Name = claim(i, types, "enum "+enum.Name, enum.Name)
+			for _, value := range enum.Values {
+				enum.Constants = append(enum.Constants, claim(i, types, "enum value "+value, enum.Name+enumValueName(value)))
+			}
+			names[i].Enums = append(names[i].Enums, enum)
+			if field.Query != "" {
+				if names[i].QueryEnums == nil {
+					names[i].QueryEnums = make(map[string]string)
+				}
+				names[i].QueryEnums[field.Query] = enum.Name
+			}
+		}
+
+		// Argument types depend on the struct and enum names above
+		if hasArgumentChecks(endpoint, names[i]) {
+			names[i].Request = claim(i, types, "request", names[i].Method+"Request")
+		}
+	}
+
+	return names
+}
+
+// enumValueName is the suffix of the constant declared for an enum value, e.g. "-1" becomes Minus1
+func enumValueName(value string) string {
+	// The enum name comes first, so a leading digit needs no prefix here
+	name := joinWords(splitWords(strings.ReplaceAll(value, "-", " Minus ")))
+	if name == "" {
+		return "Empty"
+	}
+	return name
+}
@@ -0,0 +1,109 @@
+package parser
+
+import (
+	"reddit-go-api-generator/models"
+	"strings"
+	"testing"
+)
+
+func TestExportedName(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"conversation_id", "ConversationID"},
+		{"thingId", "ThingID"},
+		{"sr_detail", "SrDetail"},
+		{"api_type", "APIType"},
+		{"link_ids", "LinkIDs"},
+		{"X-Modhash", "XModhash"},
+		{"get /api/media/asset.json", "GetAPIMediaAssetJSON"},
+		{"HTTPServer", "HTTPServer"},
+		{"2fa_code", "X2faCode"},
+		{"type", "Type"},
+		{"()", ""},
+	}
+
+	for _, test := range tests {
+		output := exportedName(test.input)
+		if output != test.expected {
+			t.Errorf("For input '%s', expected '%s' but got '%s'", test.input, test.expected, output)
+		}
+	}
+}
+
+func TestFormatProperty(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"thing_id", "thingID"},
+		{"api_type", "apiType"},
+		{"id", "id"},
+		{"type", "typeValue"},
+		{"func", "funcValue"},
+		{"string", "stringValue"},
+		{"len", "lenValue"},
+		{"payload", "payloadValue"},
+		{"fmt", "fmtValue"},
+		{"2fa_code", "x2faCode"},
+		{"('user',)", "user"},
+		{"", "value"},
+	}
+
+	for _, test := range tests {
+		output := formatProperty(test.input)
+		if output != test.expected {
+			t.Errorf("For input '%s', expected '%s' but got '%s'", test.input, test.expected, output)
+		}
+	}
+}
+
+func TestResolveNamesDisambiguates(t *testing.T) {
+	endpoints := []models.Endpoint{
+		{Method: "GET", Path: "/api/v1/me", Response: []models.Output{{Name: "name", Type: models.Primitive(models.KindString)}}},
+		{Method: "GET", Path: "/api/me", Response: []models.Output{{Name: "name", Type: models.Primitive(models.KindString)}}},
+		{Method: "GET", Path: "/me"},
+		{Method: "POST", Path: "/api/make_request"},
+		{Method: "GET", Path: "/account", Section: "account", Response: []models.Output{{Name: "sort", Type: models.EnumOf("new", "New", "-1", "")}}},
+	}
+
+	// Running twice with the endpoints reversed must give every endpoint the same names
+	reversed := make([]models.Endpoint, len(endpoints))
+	for i, endpoint := range endpoints {
+		reversed[len(endpoints)-1-i] = endpoint
+	}
+	names, reversedNames := resolveNames(endpoints), resolveNames(reversed)
+	for i := range names {
+		if got, want := reversedNames[len(names)-1-i].Method, names[i].Method; got != want {
+			t.Errorf("For input '%s', expected '%s' but got '%s'", endpoints[i].Path, want, got)
+		}
+	}
+
+	expected := []string{"GetAPIV1Me", "GetMe", "GetMe2", "PostMakeRequest", "GetAccount"}
+	for i, name := range names {
+		if name.Method != expected[i] {
+			t.Errorf("For input '%s', expected '%s' but got '%s'", endpoints[i].Path, expected[i], name.Method)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range names {
+		declared := []string{name.Response}
+		for _, enum := range name.Enums {
+			declared = append(declared, enum.Name)
+			declared = append(declared, enum.Constants...)
+		}
+		for _, identifier := range declared {
+			if identifier != "any" && seen[identifier] {
+				t.Errorf("%s is declared twice", identifier)
+			}
+			seen[identifier] = true
+		}
+	}
+
+	constants := strings.Join(names[4].Enums[0].Constants, ", ")
+	if want := "GetAccountSortEnumNew, GetAccountSortEnumNew2, GetAccountSortEnumMinus1, GetAccountSortEnumEmpty"; constants != want {
+		t.Errorf("For input '%s', expected '%s' but got '%s'", endpoints[4].Response[0].Type, want, constants)
+	}
+}
@@ -2,58 +2,102 @@ package parser
 
 import (
 	_ "embed"
+	"fmt"
+	"path"
 	"reddit-go-api-generator/models"
+	"reddit-go-api-generator/writer"
+	"strings"
 )
 
 //go:embed sdk_helpers.txt
 var sdkHelpers string
 
-// GenerateGoFunctions Generates Go functions from a list of endpoints
-func GenerateGoFunctions(endpoints []models.Endpoint) []string {
-	var functions []string
-	functions = append(functions, sdkHelpers)
+//go:embed fullname_helpers.txt
+var fullnameHelpers string
 
-	for _, endpoint := range endpoints {
-		//println(fmt.Sprintf("operating on %s", endpoint))
-		//println("reached buildFunctionName")
+//go:embed json_types_helpers.txt
+var jsonTypesHelpers string
 
-		funcName := buildFunctionName(endpoint)
-		//println("reached collectEnums")
+//go:embed validation_helpers.txt
+var validationHelpers string
 
-		enums := collectEnums(endpoint, funcName)
-		//println("reached generateEnumDefinitions")
+//go:embed media_helpers.txt
+var mediaHelpers string
 
-		enumDefs := generateEnumDefinitions(enums)
-		//println("reached generateResponseStruct")
+// GeneratedHeader marks every generated file so it can be safely replaced on the next run
+const GeneratedHeader = "// Code generated by reddigo-generator. DO NOT EDIT."
 
-		responseStruct := generateResponseStruct(endpoint, funcName)
-		//println("reached generateFunctionComment")
+// DefaultPackageName is used when Options.PackageName is empty
+const DefaultPackageName = "reddigo"
 
-		comment := generateFunctionComment(endpoint, funcName)
-		//println("reached generateFunctionSignature")
+// Options controls the shape of the generated SDK
+type Options struct {
+	// PackageName is the Go package clause of the generated files
+	PackageName string
+}
 
-		funcSignature := generateFunctionSignature(endpoint, funcName, enums)
-		//println("reached buildURL")
+func (o Options) packageName() string {
+	if o.PackageName == "" {
+		return DefaultPackageName
+	}
+	return o.PackageName
+}
 
-		urlBuild := buildURL(endpoint)
-		//println("reached buildPayload")
+// GenerateSDK renders every file of the SDK: the client package, its interfaces, the
+// runtime types and the fake server test package. File names are relative to the output directory.
+func GenerateSDK(endpoints []models.Endpoint, opts Options) []writer.File {
+	var client strings.Builder
+	for _, function := range GenerateGoFunctions(endpoints, opts) {
+		client.WriteString(function + "\n\n")
+	}
 
-		payloadBuild := buildPayload(endpoint)
-		//println("reached buildQueryParams")
+	fakeServerPackage := FakeServerPackageName(opts)
+
+	return []writer.File{
+		{Name: "reddigo.go", Content: []byte(client.String())},
+		{Name: "fullname.go", Content: []byte(generateRuntimeFile(fullnameHelpers, opts))},
+		{Name: "json_types.go", Content: []byte(generateRuntimeFile(jsonTypesHelpers, opts))},
+		{Name: "validation.go", Content: []byte(generateRuntimeFile(validationHelpers, opts))},
+		{Name: "media.go", Content: []byte(generateRuntimeFile(mediaHelpers, opts))},
+		{Name: "interfaces.go", Content: []byte(generateInterfaces(endpoints, opts))},
+		{Name: path.Join(fakeServerPackage, fakeServerPackage+".go"), Content: []byte(generateFakeServer(endpoints, opts))},
+		{Name: path.Join(fakeServerPackage, "recorder.go"), Content: []byte(generateRecorder(opts))},
+	}
+}
 
-		queryParamsBuild := buildQueryParams(endpoint)
-		//println("reached buildRequest")
+// generateRuntimeFile renders one of the embedded runtime helpers as a file of the client package
+func generateRuntimeFile(helpers string, opts Options) string {
+	return fmt.Sprintf("%s\n\npackage %s\n%s", GeneratedHeader, opts.packageName(), helpers)
+}
 
-		requestBuild := buildRequest(endpoint, funcName)
-		//println("reached buildFunctionEnd")
+// sendsJSONBody reports whether methods generated for the HTTP method marshal their payload
+func sendsJSONBody(method string) bool {
+	return method == "POST" || method == "PATCH" || method == "PUT"
+}
 
-		funcEnd := buildFunctionEnd(funcName)
-		//println("reached end")
+// GenerateGoFunctions Generates Go functions from a list of endpoints
+func GenerateGoFunctions(endpoints []models.Endpoint, opts Options) []string {
+	var functions []string
+	functions = append(functions, fmt.Sprintf("%s\n\npackage %s\n%s", GeneratedHeader, opts.packageName(), sdkHelpers))
+
+	endpoints = ExpandVariants(endpoints)
+	names := resolveNames(endpoints)
+	for i, endpoint := range endpoints {
+		enumDefs := generateEnumDefinitions(names[i].Enums)
+		responseStruct := generateResponseStruct(endpoint, names[i])
+		requestStructs := generateRequestStructs(endpoint, names[i])
+		requestType := generateRequestType(endpoint, names[i])
+		comment := generateFunctionComment(endpoint, names[i].Method)
+		funcSignature := generateFunctionSignature(endpoint, names[i])
+		validation := buildValidation(endpoint, names[i])
+		urlBuild := buildURL(endpoint)
+		payloadBuild := buildPayload(endpoint)
+		queryParamsBuild := buildQueryParams(endpoint, names[i])
+		requestBuild := buildRequest(endpoint, names[i])
+		funcEnd := buildFunctionEnd(names[i].Method)
 
-		function := enumDefs + responseStruct + comment + funcSignature + urlBuild + payloadBuild + queryParamsBuild + requestBuild + funcEnd
+		function := enumDefs + responseStruct + requestStructs + requestType + comment + funcSignature + validation + urlBuild + payloadBuild + queryParamsBuild + requestBuild + funcEnd
 		functions = append(functions, function)
-
-		//println(fmt.Sprintf("completed generating go func for %s", endpoint))
 	}
 
 	return functions
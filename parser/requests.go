@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"fmt"
+	"reddit-go-api-generator/models"
+	"strings"
+)
+
+// walkObjects calls visit for every object with fields nested in fields, parents first.
+// The path names the object within the payload or response, e.g. styles or data[] for the
+// elements of the data array.
+func walkObjects(fields []models.Field, visit func(path string, t models.TypeRef)) {
+	for _, field := range fields {
+		walkObject(field.Name, field.Type, visit)
+	}
+}
+
+func walkObject(path string, t models.TypeRef, visit func(path string, t models.TypeRef)) {
+	switch t.Kind {
+	case models.KindObject:
+		if len(t.Fields) > 0 {
+			visit(path, t)
+			for _, field := range t.Fields {
+				walkObject(path+"."+field.Name, field.Type, visit)
+			}
+		}
+	case models.KindArray:
+		if t.Elem != nil {
+			walkObject(path+"[]", *t.Elem, visit)
+		}
+	}
+}
+
+// objectStructName is the preferred name of the struct generated for a nested object, e.g.
+// PostWidgetStyles for styles and PostWidgetDataItem for data[] with the prefix PostWidget
+func objectStructName(prefix, path string) string {
+	return prefix + exportedName(strings.ReplaceAll(path, "[]", " item "))
+}
+
+// generateRequestStructs renders the structs of the nested objects in the endpoint payload,
+// each with a Validate method
+func generateRequestStructs(endpoint models.Endpoint, names endpointNames) string {
+	var structDefs string
+
+	walkObjects(endpoint.Payload, func(path string, t models.TypeRef) {
+		structName := names.Requests[path]
+
+		structDefs += fmt.Sprintf("// %s is the %s field of the %s %s payload\n", structName, path, endpoint.Method, endpoint.Path)
+		structDefs += fmt.Sprintf("type %s struct {\n", structName)
+		// Validate is a method of the struct, so no field can take its name
+		fields := newNamespace("Validate")
+		fieldNames := make([]string, len(t.Fields))
+		for i, field := range t.Fields {
+			fieldName := exportedName(field.Name)
+			if fieldName == "" {
+				fieldName = "Field"
+			}
+			fieldName = fields.claim(fieldName)
+			fieldNames[i] = fieldName
+
+			fieldType := goType(field.Type, path+"."+field.Name, names)
+			description := formatFieldDescription(field.Description)
+
+			structDefs += fmt.Sprintf("\t%s %s `json:\"%s,omitempty\"` %s\n", fieldName, fieldType, field.Name, description)
+		}
+		structDefs += "}\n\n"
+		structDefs += generateValidateMethod(structName, path, t, fieldNames, names)
+	})
+
+	return structDefs
+}
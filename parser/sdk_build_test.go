@@ -0,0 +1,200 @@
+package parser
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reddit-go-api-generator/models"
+	"testing"
+)
+
+var minHeight, maxHeight, maxLimit = 1.0, 1000.0, 100.0
+
+var meEndpoint = models.Endpoint{
+	ID:          "GET /api/v1/me",
+	Method:      "GET",
+	Path:        "/api/v1/me",
+	Section:     "account",
+	Description: "Returns the identity of the user.",
+}
+
+var commentEndpoint = models.Endpoint{
+	ID:          "POST /api/comment",
+	Method:      "POST",
+	Path:        "/api/comment",
+	Section:     "links & comments",
+	Description: "Submit a new comment or reply to a message.",
+	Payload: []models.Input{
+		{Name: "api_type", Description: "the string json", Type: models.Primitive(models.KindString)},
+		{Name: "text", Description: "raw markdown text", Type: models.Primitive(models.KindString), Constraints: &models.Constraints{MaxLength: 10000}},
+		{Name: "thing_id", Description: "fullname of parent thing", Type: models.Primitive(models.KindFullname)},
+	},
+}
+
+var infoEndpoint = models.Endpoint{
+	ID:          "GET /api/info",
+	Method:      "GET",
+	Path:        "/api/info",
+	Section:     "links & comments",
+	Description: "Return a listing of things specified by their fullnames.",
+	Response: []models.Output{
+		{Name: "created_utc", Type: models.Primitive(models.KindTimestamp)},
+		{Name: "edited", Type: models.Primitive(models.KindBoolOrTimestamp)},
+		{Name: "score", Type: models.Primitive(models.KindStringOrNumber)},
+	},
+}
+
+var newEndpoint = models.Endpoint{
+	ID:          "GET /new",
+	Method:      "GET",
+	Path:        "/new",
+	Section:     "listings",
+	Description: "This endpoint is a listing.",
+	Response: []models.Output{
+		{Name: "kind", Type: models.Primitive(models.KindString)},
+		{Name: "data", Type: models.ObjectOf(
+			models.Field{Name: "after", Type: models.Primitive(models.KindFullname)},
+			models.Field{Name: "children", Type: models.ArrayOf(models.ObjectOf(
+				models.Field{Name: "data", Type: models.ObjectOf(
+					models.Field{Name: "created_utc", Type: models.Primitive(models.KindTimestamp)},
+					models.Field{Name: "edited", Type: models.Primitive(models.KindBoolOrTimestamp)},
+				)},
+			))},
+		)},
+	},
+}
+
+var hotEndpoint = models.Endpoint{
+	ID:                "GET /hot",
+	Method:            "GET",
+	Path:              "/hot",
+	OptionalSubreddit: true,
+	Section:           "listings",
+	Description:       "This endpoint is a listing.",
+	URLParams:         []string{"subreddit"},
+	QueryParams: []models.Parameter{
+		{Name: "after", Description: "fullname of a thing", Type: models.Primitive(models.KindFullname)},
+		{Name: "limit", Description: "the maximum number of items desired (maximum: 100)", Type: models.Primitive(models.KindInt), Constraints: &models.Constraints{Max: &maxLimit}},
+		{Name: "g", Description: "one of (GLOBAL, US)", Type: models.EnumOf("GLOBAL", "US")},
+		{Name: "sr_detail", Description: "expand subreddits", Type: models.Primitive(models.KindBool)},
+	},
+}
+
+var aboutEndpoint = models.Endpoint{
+	ID:                "GET /about/{where}",
+	Method:            "GET",
+	Path:              "/about/{where}",
+	OptionalSubreddit: true,
+	Variants:          []string{"/about/banned", "/about/muted"},
+	Section:           "subreddits",
+	Description:       "Lists users related to the subreddit.",
+	URLParams:         []string{"subreddit", "where"},
+}
+
+var widgetEndpoint = models.Endpoint{
+	ID:                "POST /api/widget",
+	Method:            "POST",
+	Path:              "/api/widget",
+	OptionalSubreddit: true,
+	Section:           "widgets",
+	Description:       "Add and return a widget to the specified subreddit.",
+	Payload: []models.Input{
+		{Name: "data", Type: models.ArrayOf(models.ObjectOf(
+			models.Field{Name: "height", Description: "an integer between 1 and 1000", Type: models.Primitive(models.KindInt), Constraints: &models.Constraints{Min: &minHeight, Max: &maxHeight}},
+			models.Field{Name: "url", Description: "a valid URL of a reddit-hosted image", Type: models.Primitive(models.KindString)},
+		))},
+		{Name: "shortName", Description: "a string no longer than 30 characters", Type: models.Primitive(models.KindString)},
+		{Name: "styles", Type: models.ObjectOf(
+			models.Field{Name: "headerColor", Description: "a 6-digit rgb hex color", Type: models.Primitive(models.KindString)},
+		)},
+	},
+}
+
+var highlightEndpoint = models.Endpoint{
+	ID:          "DELETE /api/mod/conversations/{conversation_id}/highlight",
+	Method:      "DELETE",
+	Path:        "/api/mod/conversations/{conversation_id}/highlight",
+	Description: "Removes a highlight from a conversation.",
+}
+
+// buildTestEndpoints covers the shapes the generator has to handle: plain GETs,
+// path placeholders, an optional subreddit, path variants, query parameters, enums,
+// flat and nested JSON payloads with constraints and flat and nested responses with timestamps and mixed types
+var buildTestEndpoints = []models.Endpoint{
+	meEndpoint, commentEndpoint, infoEndpoint, newEndpoint, hotEndpoint, aboutEndpoint, widgetEndpoint, highlightEndpoint,
+}
+
+// TestGeneratedSDKBuilds writes the SDK generated for every fixture endpoint into a
+// throwaway module and runs go vet on it, so the emitted code is compiled for real
+func TestGeneratedSDKBuilds(t *testing.T) {
+	runGeneratedSDK(t, buildTestEndpoints, "", "vet", "./...")
+}
+
+// TestRuntimeHelpers runs the tests in testdata/runtime against the SDK generated for the
+// endpoints each runtime helper needs, one go test run per helper
+func TestRuntimeHelpers(t *testing.T) {
+	tests := []struct {
+		helper    string
+		testFile  string
+		endpoints []models.Endpoint
+	}{
+		{"reddigotest_helpers.txt", "fake_server_test.go", buildTestEndpoints},
+		{"fullname_helpers.txt", "fullname_test.go", nil},
+		{"json_types_helpers.txt", "json_types_test.go", []models.Endpoint{infoEndpoint, newEndpoint}},
+		{"validation_helpers.txt", "validation_test.go", []models.Endpoint{commentEndpoint, hotEndpoint, widgetEndpoint}},
+		{"media_helpers.txt", "media_test.go", nil},
+		{"sdk_helpers.txt", "middleware_test.go", []models.Endpoint{meEndpoint, hotEndpoint}},
+		{"sdk_helpers.txt", "safety_test.go", []models.Endpoint{meEndpoint, commentEndpoint, highlightEndpoint}},
+		{"recorder_helpers.txt", "recorder_test.go", []models.Endpoint{commentEndpoint, hotEndpoint}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testFile, func(t *testing.T) {
+			t.Parallel()
+			runGeneratedSDK(t, test.endpoints, test.testFile, "test", "./...")
+		})
+	}
+}
+
+// runGeneratedSDK writes the SDK generated for endpoints and the named file from
+// testdata/runtime into a throwaway module and runs the go command on it
+func runGeneratedSDK(t *testing.T, endpoints []models.Endpoint, testFile string, args ...string) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping go toolchain invocation in short mode")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example.com/sdk\n\ngo 1.23\n")
+	for _, file := range GenerateSDK(endpoints, Options{}) {
+		writeTestFile(t, filepath.Join(dir, file.Name), string(file.Content))
+	}
+	if testFile != "" {
+		content, err := os.ReadFile(filepath.Join("testdata", "runtime", testFile))
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeTestFile(t, filepath.Join(dir, testFile), string(content))
+	}
+
+	cmd := exec.Command(goBin, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off", "GOWORK=off")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go %s failed: %v\n%s", args[0], err, output)
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,105 @@
+package reddigo_test
+
+import (
+	"testing"
+
+	reddigo "example.com/sdk"
+	"example.com/sdk/reddigotest"
+)
+
+func TestClientAgainstFakeServer(t *testing.T) {
+	server := reddigotest.NewServer(t)
+	defer server.Close()
+
+	server.Stub("GetMe", 200, map[string]any{"name": "spez"})
+	server.ExpectParams("PostComment", "text", "thing_id")
+
+	var sdk reddigo.API = reddigo.NewReddiGoSDK(reddigo.RedditConfig{BaseURL: server.URL, UserAgent: "test"})
+
+	var account reddigo.AccountAPI = sdk
+	me, err := account.GetMe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if me.(map[string]any)["name"] != "spez" {
+		t.Errorf("unexpected identity %v", me)
+	}
+
+	link := reddigo.NewFullname(reddigo.KindLink, 12345)
+	if _, err := sdk.PostComment("json", "hello", link); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sdk.GetHot("golang", link, reddigo.Ptr(10), reddigo.GetHotGEnumUS, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sdk.GetHot("", "", reddigo.Ptr(0), "", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sdk.GetAboutBanned("golang"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sdk.GetAboutWhere("golang", "contributors"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sdk.DeleteModConversationsConversationIDHighlight("2x8k"); err != nil {
+		t.Fatal(err)
+	}
+
+	data := []reddigo.PostWidgetDataItem{{Height: 100, URL: "https://i.redd.it/a.png"}}
+	if _, err := sdk.PostWidget("golang", data, "pics", reddigo.PostWidgetStyles{HeaderColor: "#AABBCC"}); err != nil {
+		t.Fatal(err)
+	}
+	widget := server.CallsTo("PostWidget")
+	if len(widget) != 1 || widget[0].Body["shortName"] != "pics" {
+		t.Fatalf("unexpected widget calls %+v", widget)
+	}
+	if styles, _ := widget[0].Body["styles"].(map[string]any); styles["headerColor"] != "#AABBCC" {
+		t.Errorf("unexpected styles %+v", widget[0].Body["styles"])
+	}
+	if items, _ := widget[0].Body["data"].([]any); len(items) != 1 || items[0].(map[string]any)["height"] != float64(100) {
+		t.Errorf("unexpected data %+v", widget[0].Body["data"])
+	}
+
+	calls := server.CallsTo("GetHot")
+	if len(calls) != 2 || calls[0].PathParams["subreddit"] != "golang" || calls[0].Query.Get("limit") != "10" {
+		t.Errorf("unexpected calls %+v", calls)
+	}
+	if len(calls) == 2 && (calls[1].Path != "/hot" || calls[1].PathParams["subreddit"] != "" || calls[1].Query.Has("after")) {
+		t.Errorf("expected the front page listing, got %+v", calls[1])
+	}
+	if len(calls) == 2 && (calls[0].Query.Get("g") != "US" || calls[0].Query.Get("sr_detail") != "true") {
+		t.Errorf("unexpected enum and bool query parameters %v", calls[0].Query)
+	}
+	if len(calls) == 2 && (calls[1].Query.Get("limit") != "0" || calls[1].Query.Has("g") || calls[1].Query.Has("sr_detail")) {
+		t.Errorf("expected only limit=0 to be sent, got %v", calls[1].Query)
+	}
+	if banned := server.CallsTo("GetAboutBanned"); len(banned) != 1 || banned[0].PathParams["subreddit"] != "golang" {
+		t.Errorf("unexpected variant calls %+v", banned)
+	}
+	if where := server.CallsTo("GetAboutWhere"); len(where) != 1 || where[0].PathParams["where"] != "contributors" {
+		t.Errorf("unexpected calls %+v", where)
+	}
+	if calls[0].Query.Get("after") != "t3_9ix" {
+		t.Errorf("unexpected after %q", calls[0].Query.Get("after"))
+	}
+	if comment := server.CallsTo("PostComment"); len(comment) != 1 || comment[0].Body["text"] != "hello" || comment[0].Body["thing_id"] != "t3_9ix" {
+		t.Errorf("unexpected comment calls %+v", comment)
+	}
+}
+
+func TestFakeServerReportsUnknownParams(t *testing.T) {
+	server := reddigotest.NewServer(nil)
+	defer server.Close()
+
+	sdk := reddigo.NewReddiGoSDK(reddigo.RedditConfig{BaseURL: server.URL})
+	if _, err := sdk.MakeRequest("GET", "/api/v1/me?bogus=1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sdk.MakeRequest("GET", "/not/documented", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if problems := server.Problems(); len(problems) != 2 {
+		t.Errorf("expected 2 problems, got %v", problems)
+	}
+}
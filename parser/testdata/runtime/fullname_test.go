@@ -0,0 +1,27 @@
+package reddigo_test
+
+import (
+	"testing"
+
+	reddigo "example.com/sdk"
+)
+
+func TestFullname(t *testing.T) {
+	fullname, err := reddigo.ParseFullname("t3_15bfi0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fullname.Kind() != reddigo.KindLink || fullname.Kind().String() != "Link" || fullname.ID() != "15bfi0" {
+		t.Errorf("unexpected kind %q or ID %q", fullname.Kind(), fullname.ID())
+	}
+	number, err := fullname.Number()
+	if err != nil || reddigo.NewFullname(reddigo.KindLink, number) != fullname {
+		t.Errorf("expected %d to convert back to %s, got error %v", number, fullname, err)
+	}
+
+	for _, invalid := range []string{"", "15bfi0", "t9_15bfi0", "t3_", "t3_15BFI0", "t3_15-fi0"} {
+		if _, err := reddigo.ParseFullname(invalid); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package reddigo_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	reddigo "example.com/sdk"
+	"example.com/sdk/reddigotest"
+)
+
+func TestResponseTypes(t *testing.T) {
+	server := reddigotest.NewServer(t)
+	defer server.Close()
+	sdk := reddigo.NewReddiGoSDK(reddigo.RedditConfig{BaseURL: server.URL})
+
+	server.Stub("GetInfo", 200, map[string]any{"created_utc": 1700000000.5, "edited": false, "score": 42})
+	info, err := sdk.GetInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.CreatedUtc.Unix() != 1700000000 || info.CreatedUtc.Nanosecond() != 500000000 {
+		t.Errorf("unexpected created_utc %v", info.CreatedUtc)
+	}
+	if score, err := info.Score.Int64(); info.Edited.Set || err != nil || score != 42 {
+		t.Errorf("unexpected edited %+v or score %q", info.Edited, info.Score)
+	}
+
+	server.Stub("GetInfo", 200, map[string]any{"created_utc": nil, "edited": 1700000100, "score": "•"})
+	info, err = sdk.GetInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.CreatedUtc.IsZero() || !info.Edited.Set || info.Edited.Time.Unix() != 1700000100 || info.Score != "•" {
+		t.Errorf("unexpected response %+v", info)
+	}
+
+	content, err := json.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "\"created_utc\":null") || !strings.Contains(string(content), "\"edited\":1700000100") {
+		t.Errorf("unexpected encoding %s", content)
+	}
+}
+
+func TestNestedResponseTypes(t *testing.T) {
+	server := reddigotest.NewServer(t)
+	defer server.Close()
+	sdk := reddigo.NewReddiGoSDK(reddigo.RedditConfig{BaseURL: server.URL})
+
+	server.Stub("GetNew", 200, map[string]any{"kind": "Listing", "data": map[string]any{
+		"after": nil,
+		"children": []any{
+			map[string]any{"data": map[string]any{"created_utc": 1700000000, "edited": false}},
+			map[string]any{"data": map[string]any{"created_utc": 1700000000, "edited": 1700000100.25}},
+		},
+	}})
+	listing, err := sdk.GetNew()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	children := listing.Data.Children
+	if listing.Data.After != "" || len(children) != 2 {
+		t.Fatalf("unexpected listing %+v", listing)
+	}
+	if children[0].Data.CreatedUtc.Unix() != 1700000000 || children[0].Data.Edited.Set {
+		t.Errorf("unexpected first child %+v", children[0].Data)
+	}
+	if !children[1].Data.Edited.Set || children[1].Data.Edited.Time.Unix() != 1700000100 {
+		t.Errorf("unexpected second child %+v", children[1].Data)
+	}
+}
@@ -0,0 +1,123 @@
+package reddigo_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	reddigo "example.com/sdk"
+	"example.com/sdk/reddigotest"
+)
+
+func TestSubmitMedia(t *testing.T) {
+	server := reddigotest.NewServer(t)
+	defer server.Close()
+	sdk := reddigo.NewReddiGoSDK(reddigo.RedditConfig{BaseURL: server.URL})
+
+	// A PNG without an extension is detected from its first bytes
+	content := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{1}, 100000)...)
+	var sent, total int64
+	asset, _, err := sdk.SubmitMedia(reddigo.MediaUpload{
+		Name:    "cat",
+		Content: bytes.NewReader(content),
+		Size:    int64(len(content)),
+		OnProgress: func(s, t int64) {
+			sent, total = s, t
+		},
+	}, reddigo.MediaPost{Subreddit: "pics", Title: "Cat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != int64(len(content)) || total != int64(len(content)) {
+		t.Errorf("expected progress to reach %d, got %d of %d", len(content), sent, total)
+	}
+
+	uploads := server.Uploads()
+	if len(uploads) != 1 {
+		t.Fatalf("expected 1 upload, got %d", len(uploads))
+	}
+	upload := uploads[0]
+	if upload.ContentType != "image/png" || upload.FileName != "cat" || !bytes.Equal(upload.Content, content) {
+		t.Errorf("unexpected upload %q of %s with %d bytes", upload.FileName, upload.ContentType, len(upload.Content))
+	}
+	if upload.ContentLength <= int64(len(content)) || upload.Fields["key"] == "" {
+		t.Errorf("expected a sized upload with the leased key, got length %d and fields %v", upload.ContentLength, upload.Fields)
+	}
+
+	lease := server.CallsTo("UploadMedia")
+	if len(lease) != 1 || lease[0].Body["mimetype"] != "image/png" || lease[0].Body["filepath"] != "cat" {
+		t.Errorf("unexpected lease requests %+v", lease)
+	}
+	if asset.ID == "" || !strings.HasSuffix(asset.URL, "/"+upload.Fields["key"]) {
+		t.Errorf("unexpected asset %+v", asset)
+	}
+
+	submit := server.CallsTo("SubmitMedia")
+	if len(submit) != 1 || submit[0].Body["kind"] != "image" || submit[0].Body["url"] != asset.URL || submit[0].Body["sr"] != "pics" {
+		t.Errorf("unexpected submit requests %+v", submit)
+	}
+
+	// Text files cannot be posted
+	_, _, err = sdk.SubmitMedia(reddigo.MediaUpload{Name: "notes.txt", Content: strings.NewReader("hello")}, reddigo.MediaPost{Subreddit: "pics", Title: "Notes"})
+	if err == nil || !strings.Contains(err.Error(), "text/plain") {
+		t.Errorf("expected text media to be rejected, got %v", err)
+	}
+}
+
+func TestUploadMediaStorage(t *testing.T) {
+	tests := []struct {
+		name     string
+		reply    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "location", reply: "<PostResponse><Location>https://i.redd.it/cat%20one.png</Location></PostResponse>", expected: "https://i.redd.it/cat one.png"},
+		{name: "empty body", reply: "", expected: "/uploads/cat.png"},
+		{name: "not XML", reply: "upstream error", wantErr: true},
+	}
+
+	for _, test := range tests {
+		var userAgent string
+		storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userAgent = r.UserAgent()
+			io.WriteString(w, test.reply)
+		}))
+
+		server := reddigotest.NewServer(t)
+		server.Stub("UploadMedia", 200, map[string]any{
+			"args":  map[string]any{"action": storage.URL + "/uploads", "fields": []any{map[string]any{"name": "key", "value": "cat.png"}}},
+			"asset": map[string]any{"asset_id": "a1"},
+		})
+
+		var intercepted []string
+		record := func(next reddigo.Doer) reddigo.Doer {
+			return reddigo.DoerFunc(func(req *http.Request) (*http.Response, error) {
+				intercepted = append(intercepted, req.URL.Path)
+				return next.Do(req)
+			})
+		}
+		sdk := reddigo.NewReddiGoSDK(reddigo.RedditConfig{BaseURL: server.URL, UserAgent: "bot/1.0", Middleware: []reddigo.Middleware{record}})
+
+		asset, err := sdk.UploadMedia(reddigo.MediaUpload{Name: "cat.png", Content: strings.NewReader("\x89PNG\r\n\x1a\n")})
+		switch {
+		case test.wantErr && err == nil:
+			t.Errorf("%s: expected an error, got %+v", test.name, asset)
+		case !test.wantErr && err != nil:
+			t.Errorf("%s: unexpected error %v", test.name, err)
+		case !test.wantErr && !strings.HasSuffix(asset.URL, test.expected):
+			t.Errorf("%s: expected a URL ending in %s, got %s", test.name, test.expected, asset.URL)
+		}
+		if len(intercepted) != 1 || intercepted[0] != "/api/media/asset.json" {
+			t.Errorf("%s: expected only the lease to pass the middleware, got %v", test.name, intercepted)
+		}
+		if userAgent == "bot/1.0" {
+			t.Errorf("%s: expected the storage upload without the API user agent", test.name)
+		}
+
+		server.Close()
+		storage.Close()
+	}
+}
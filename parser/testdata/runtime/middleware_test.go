@@ -0,0 +1,56 @@
+package reddigo_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	reddigo "example.com/sdk"
+	"example.com/sdk/reddigotest"
+)
+
+func TestMiddleware(t *testing.T) {
+	server := reddigotest.NewServer(t)
+	defer server.Close()
+
+	var order []string
+	trace := func(name string) reddigo.Middleware {
+		return func(next reddigo.Doer) reddigo.Doer {
+			return reddigo.DoerFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				req.Header.Set("X-Trace", strings.Join(order, ","))
+				return next.Do(req)
+			})
+		}
+	}
+	failing := errors.New("injected fault")
+	fault := func(next reddigo.Doer) reddigo.Doer {
+		return reddigo.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == "/api/v1/me" {
+				return nil, failing
+			}
+			return next.Do(req)
+		})
+	}
+
+	sdk := reddigo.NewReddiGoSDK(reddigo.RedditConfig{
+		BaseURL:    server.URL,
+		Middleware: []reddigo.Middleware{trace("outer"), trace("inner"), fault},
+	})
+
+	if _, err := sdk.GetHot("", "", nil, "", false); err != nil {
+		t.Fatal(err)
+	}
+	calls := server.CallsTo("GetHot")
+	if len(calls) != 1 || calls[0].Header.Get("X-Trace") != "outer,inner" {
+		t.Errorf("expected middleware to run outer first, got %+v", calls)
+	}
+
+	if _, err := sdk.GetMe(); !errors.Is(err, failing) {
+		t.Errorf("expected the injected fault, got %v", err)
+	}
+	if calls := server.CallsTo("GetMe"); len(calls) != 0 {
+		t.Errorf("expected the faulted request not to be sent, got %+v", calls)
+	}
+}
@@ -0,0 +1,161 @@
+package reddigo_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	reddigo "example.com/sdk"
+	"example.com/sdk/reddigotest"
+)
+
+func TestRecorder(t *testing.T) {
+	cassette := filepath.Join(t.TempDir(), "cassettes", "comment.json")
+
+	server := reddigotest.NewServer(t)
+	server.Stub("PostComment", 200, map[string]any{"id": "c1", "access_token": "secret-token"})
+	recorder, err := reddigotest.NewRecorder(cassette, reddigotest.ModeReplayOrRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !recorder.Recording() {
+		t.Fatal("expected a missing cassette to be recorded")
+	}
+	sdk := reddigo.NewReddiGoSDK(reddigo.RedditConfig{BaseURL: server.URL, AccessToken: "live-token", HTTPClient: recorder.Client()})
+	recordedResp, err := sdk.PostComment("json", "hello", "t3_abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sdk.GetHot("", "t3_abc", reddigo.Ptr(10), "", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+
+	content, err := os.ReadFile(cassette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), "live-token") || strings.Contains(string(content), "secret-token") {
+		t.Errorf("expected secrets to be redacted, got %s", content)
+	}
+
+	// The server is gone, so every response comes from the cassette
+	recorder, err = reddigotest.NewRecorder(cassette, reddigotest.ModeReplayOrRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recorder.Recording() {
+		t.Fatal("expected an existing cassette to be replayed")
+	}
+	sdk = reddigo.NewReddiGoSDK(reddigo.RedditConfig{BaseURL: "http://replay.invalid", HTTPClient: recorder.Client()})
+
+	// Query parameters match in any order
+	if _, err := sdk.MakeRequest("GET", "/hot?limit=10&after=t3_abc", nil); err != nil {
+		t.Errorf("expected the reordered query to replay, got %v", err)
+	}
+	replayedResp, err := sdk.PostComment("json", "hello", "t3_abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, _ := json.Marshal(replayedResp)
+	recorded, _ := json.Marshal(recordedResp)
+	if string(replayed) == string(recorded) || !strings.Contains(string(replayed), "\"c1\"") {
+		t.Errorf("expected the redacted response, recorded %s, replayed %s", recorded, replayed)
+	}
+
+	if _, err := sdk.PostComment("json", "a different comment", "t3_abc"); err == nil {
+		t.Error("expected an unrecorded request to fail")
+	}
+}
+
+func TestRecorderKeepsJSONBodies(t *testing.T) {
+	cassette := filepath.Join(t.TempDir(), "json.json")
+	// A plain server, the fake one rejects the undocumented fields
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+	recorder, err := reddigotest.NewRecorder(cassette, reddigotest.ModeRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sdk := reddigo.NewReddiGoSDK(reddigo.RedditConfig{BaseURL: server.URL, HTTPClient: recorder.Client()})
+
+	bodies := []string{
+		`{"z":1,"id":12345678901234567890,"text":"<b>&</b>"}`,
+		`{"z":[{"access_token":"secret"}],"id":12345678901234567890,"text":"<b>&</b>"}`,
+	}
+	for _, body := range bodies {
+		resp, err := sdk.MakeRequest("POST", "/api/comment", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if err := recorder.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(cassette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved reddigotest.Cassette
+	if err := json.Unmarshal(content, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.Interactions) != 2 {
+		t.Fatalf("expected 2 interactions, got %d", len(saved.Interactions))
+	}
+	if got := saved.Interactions[0].Request.Text; got != bodies[0] {
+		t.Errorf("expected a body without secrets to be kept as sent, got %s", got)
+	}
+	got := saved.Interactions[1].Request.Text
+	if strings.Contains(got, "secret") || !strings.Contains(got, "12345678901234567890") || !strings.Contains(got, "<b>&</b>") {
+		t.Errorf("expected the secret to be redacted with the rest kept, got %s", got)
+	}
+}
+
+func TestRecorderReplaysMedia(t *testing.T) {
+	cassette := filepath.Join(t.TempDir(), "media.json")
+	// Bytes that are not valid UTF-8 must survive the cassette unchanged
+	content := append([]byte("\x89PNG\r\n\x1a\n"), 0xff, 0xfe, 0x00, 0x80, 0xc3)
+	submit := func(sdk *reddigo.ReddiGoSDK) (reddigo.MediaAsset, error) {
+		asset, _, err := sdk.SubmitMedia(reddigo.MediaUpload{Name: "cat.png", Content: bytes.NewReader(content)}, reddigo.MediaPost{Subreddit: "pics", Title: "Cat"})
+		return asset, err
+	}
+
+	server := reddigotest.NewServer(t)
+	recorder, err := reddigotest.NewRecorder(cassette, reddigotest.ModeRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded, err := submit(reddigo.NewReddiGoSDK(reddigo.RedditConfig{BaseURL: server.URL, HTTPClient: recorder.Client()}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+
+	recorder, err = reddigotest.NewRecorder(cassette, reddigotest.ModeReplay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := submit(reddigo.NewReddiGoSDK(reddigo.RedditConfig{BaseURL: "http://replay.invalid", HTTPClient: recorder.Client()}))
+	if err != nil {
+		t.Fatalf("expected the upload to replay, got %v", err)
+	}
+	if replayed != recorded {
+		t.Errorf("expected the recorded asset %+v, got %+v", recorded, replayed)
+	}
+}
@@ -0,0 +1,48 @@
+package reddigo_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	reddigo "example.com/sdk"
+	"example.com/sdk/reddigotest"
+)
+
+func TestSafetyModes(t *testing.T) {
+	server := reddigotest.NewServer(t)
+	defer server.Close()
+
+	readOnly := reddigo.NewReddiGoSDK(reddigo.RedditConfig{BaseURL: server.URL, ReadOnly: true, DryRun: true})
+	if _, err := readOnly.GetMe(); err != nil {
+		t.Errorf("expected GET requests to be sent, got %v", err)
+	}
+	if _, err := readOnly.PostComment("json", "hello", "t3_abc"); !errors.Is(err, reddigo.ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly, got %v", err)
+	}
+	if _, err := readOnly.DeleteModConversationsConversationIDHighlight("abc"); !errors.Is(err, reddigo.ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly, got %v", err)
+	}
+
+	var logs bytes.Buffer
+	dryRun := reddigo.NewReddiGoSDK(reddigo.RedditConfig{BaseURL: server.URL, DryRun: true, Logger: slog.New(slog.NewTextHandler(&logs, nil))})
+	if _, err := dryRun.PostComment("json", "hello", "t3_abc"); err != nil {
+		t.Errorf("expected a synthetic success, got %v", err)
+	}
+	asset, _, err := dryRun.SubmitMedia(reddigo.MediaUpload{Name: "cat.png", Content: strings.NewReader("\x89PNG\r\n\x1a\n")}, reddigo.MediaPost{Subreddit: "pics", Title: "Cat"})
+	if err != nil || asset.ContentType != "image/png" {
+		t.Errorf("expected a skipped upload, got %+v and %v", asset, err)
+	}
+	if !strings.Contains(logs.String(), "/api/comment") || !strings.Contains(logs.String(), "/api/submit") {
+		t.Errorf("expected skipped requests to be logged, got %s", logs.String())
+	}
+
+	if calls := server.Calls(); len(calls) != 1 || calls[0].Route != "GetMe" {
+		t.Errorf("expected only the GET request to be sent, got %+v", calls)
+	}
+	if uploads := server.Uploads(); len(uploads) != 0 {
+		t.Errorf("expected no uploads, got %d", len(uploads))
+	}
+}
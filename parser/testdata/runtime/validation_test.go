@@ -0,0 +1,66 @@
+package reddigo_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	reddigo "example.com/sdk"
+	"example.com/sdk/reddigotest"
+)
+
+func TestValidation(t *testing.T) {
+	server := reddigotest.NewServer(t)
+	defer server.Close()
+	sdk := reddigo.NewReddiGoSDK(reddigo.RedditConfig{BaseURL: server.URL})
+
+	tests := []struct {
+		name  string
+		call  func() error
+		field string
+	}{
+		{"text too long", func() error {
+			_, err := sdk.PostComment("json", strings.Repeat("a", 10001), reddigo.NewFullname(reddigo.KindLink, 1))
+			return err
+		}, "text"},
+		{"invalid fullname", func() error {
+			_, err := sdk.PostComment("json", "hello", "t3_")
+			return err
+		}, "thing_id"},
+		{"limit above the maximum", func() error {
+			_, err := sdk.GetHot("", "", reddigo.Ptr(500), "", false)
+			return err
+		}, "limit"},
+		{"g not a documented value", func() error {
+			_, err := sdk.GetHot("", "", nil, "EU", false)
+			return err
+		}, "g"},
+		{"nested value out of range", func() error {
+			_, err := sdk.PostWidget("", []reddigo.PostWidgetDataItem{{Height: 100}, {Height: 5000}}, "pics", reddigo.PostWidgetStyles{})
+			return err
+		}, "data[1].height"},
+	}
+
+	for _, test := range tests {
+		var errs reddigo.ValidationErrors
+		if err := test.call(); !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != test.field {
+			t.Errorf("%s: expected a validation error for %s, got %v", test.name, test.field, err)
+		}
+	}
+
+	if calls := server.Calls(); len(calls) != 0 {
+		t.Errorf("expected invalid requests not to be sent, got %+v", calls)
+	}
+	if err := (reddigo.PostWidgetDataItem{Height: 10}).Validate(); err != nil {
+		t.Errorf("unexpected error for a valid item: %v", err)
+	}
+
+	// Requests can be checked before calling the method
+	var errs reddigo.ValidationErrors
+	if err := (reddigo.GetHotRequest{Limit: reddigo.Ptr(500)}).Validate(); !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != "limit" {
+		t.Errorf("expected a validation error for limit, got %v", err)
+	}
+	if err := (reddigo.PostCommentRequest{Text: "hello", ThingID: reddigo.NewFullname(reddigo.KindLink, 1)}).Validate(); err != nil {
+		t.Errorf("unexpected error for a valid request: %v", err)
+	}
+}
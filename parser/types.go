@@ -0,0 +1,57 @@
+package parser
+
+import "reddit-go-api-generator/models"
+
+// goType returns the Go type generated for t. Objects use the struct named for their path
+// in names.Requests and fall back to a map when there is none.
+func goType(t models.TypeRef, path string, names endpointNames) string {
+	switch t.Kind {
+	case models.KindString, models.KindEnum:
+		return "string"
+	case models.KindFullname:
+		return "Fullname"
+	case models.KindInt:
+		return "int"
+	case models.KindFloat:
+		return "float64"
+	case models.KindTimestamp:
+		return "Timestamp"
+	case models.KindBoolOrTimestamp:
+		return "BoolOrTimestamp"
+	case models.KindStringOrNumber:
+		return "StringOrNumber"
+	case models.KindBool:
+		return "bool"
+	case models.KindArray:
+		if t.Elem == nil {
+			return "[]interface{}"
+		}
+		return "[]" + goType(*t.Elem, path+"[]", names)
+	case models.KindObject:
+		if name, ok := names.Requests[path]; ok {
+			return name
+		}
+		return "map[string]interface{}"
+	}
+	return "interface{}"
+}
+
+// queryParamType returns the Go type of a query parameter. Enums use the type generated for
+// the parameter and numbers are pointers, so zero can be sent and nil leaves them out.
+func queryParamType(param models.Parameter, names endpointNames) string {
+	switch param.Type.Kind {
+	case models.KindFullname:
+		return "Fullname"
+	case models.KindEnum:
+		if name, ok := names.QueryEnums[param.Name]; ok {
+			return name
+		}
+	case models.KindInt:
+		return "*int"
+	case models.KindFloat:
+		return "*float64"
+	case models.KindBool:
+		return "bool"
+	}
+	return "string"
+}
@@ -0,0 +1,176 @@
+package parser
+
+import (
+	"fmt"
+	"reddit-go-api-generator/models"
+	"strconv"
+	"strings"
+)
+
+// validationChecks renders the statements that record in errs why expr, a value of Go type
+// goTyp and scraped type t, breaks its documented constraints. fieldExpr is the Go expression
+// of the name reported for the value and path locates it in the payload, as in walkObjects.
+func validationChecks(t models.TypeRef, c *models.Constraints, expr, goTyp, fieldExpr, path string, names endpointNames, indent string) string {
+	var checks string
+	check := func(format string, args ...any) {
+		checks += indent + fmt.Sprintf(format, args...) + "\n"
+	}
+
+	switch {
+	case t.Kind == models.KindFullname && goTyp == "Fullname":
+		check("errs.fullname(%s, %s)", fieldExpr, expr)
+	case t.Kind == models.KindEnum && len(t.Values) > 0:
+		values := make([]string, len(t.Values))
+		for i, value := range t.Values {
+			values[i] = strconv.Quote(value)
+		}
+		value := expr
+		if goTyp != "string" {
+			// Enum query parameters have their own string type
+			value = fmt.Sprintf("string(%s)", expr)
+		}
+		check("errs.oneOf(%s, %s, %s)", fieldExpr, value, strings.Join(values, ", "))
+	case t.Kind == models.KindObject:
+		if _, ok := names.Requests[path]; ok {
+			check("errs.nested(%s, %s.Validate())", fieldExpr, expr)
+		}
+	case t.Kind == models.KindArray && t.Elem != nil && t.Elem.Kind != models.KindArray:
+		// Only the elements of arrays directly under a named field are checked, with their index
+		name, err := strconv.Unquote(fieldExpr)
+		if err != nil {
+			break
+		}
+		elemType := strings.TrimPrefix(goTyp, "[]")
+		elemField := fmt.Sprintf("fmt.Sprintf(%q, i)", name+"[%d]")
+		if body := validationChecks(*t.Elem, nil, "item", elemType, elemField, path+"[]", names, indent+"\t"); body != "" {
+			check("for i, item := range %s {", expr)
+			checks += body
+			check("}")
+		}
+	}
+
+	if c == nil {
+		return checks
+	}
+
+	switch goTyp {
+	case "string":
+		if c.MinLength > 0 {
+			check("errs.minLength(%s, %s, %d)", fieldExpr, expr, c.MinLength)
+		}
+		if c.MaxLength > 0 {
+			check("errs.maxLength(%s, %s, %d)", fieldExpr, expr, c.MaxLength)
+		}
+	case "int", "float64":
+		checks += rangeChecks(c, fmt.Sprintf("float64(%s)", expr), fieldExpr, indent)
+	case "*int", "*float64":
+		// Optional query parameters are only checked when set
+		if ranges := rangeChecks(c, fmt.Sprintf("float64(*%s)", expr), fieldExpr, indent+"\t"); ranges != "" {
+			check("if %s != nil {", expr)
+			checks += ranges
+			check("}")
+		}
+	}
+
+	return checks
+}
+
+// rangeChecks renders the checks of a float64 expression against the bounds in c
+func rangeChecks(c *models.Constraints, expr, fieldExpr, indent string) string {
+	var checks string
+	if c.Min != nil {
+		checks += fmt.Sprintf("%serrs.min(%s, %s, %s)\n", indent, fieldExpr, expr, strconv.FormatFloat(*c.Min, 'f', -1, 64))
+	}
+	if c.Max != nil {
+		checks += fmt.Sprintf("%serrs.max(%s, %s, %s)\n", indent, fieldExpr, expr, strconv.FormatFloat(*c.Max, 'f', -1, 64))
+	}
+	return checks
+}
+
+// requestArguments returns the query and body arguments of the method, the struct field of
+// each in its request type and the checks of the documented constraints of the request r
+func requestArguments(endpoint models.Endpoint, names endpointNames) ([]Param, []string, string) {
+	var params []Param
+	var fieldNames []string
+	var checks string
+	// Validate is a method of the request type, so no field can take its name
+	fields := newNamespace("Validate")
+	for _, param := range collectMethodParams(endpoint, names) {
+		if param.In == "path" {
+			continue
+		}
+		fieldName := exportedName(param.WireName)
+		if fieldName == "" {
+			fieldName = "Field"
+		}
+		fieldName = fields.claim(fieldName)
+
+		params = append(params, param)
+		fieldNames = append(fieldNames, fieldName)
+		checks += validationChecks(param.ModelType, param.Constraints, "r."+fieldName, param.Type, strconv.Quote(param.WireName), param.WireName, names, "\t")
+	}
+	return params, fieldNames, checks
+}
+
+// hasArgumentChecks reports whether an argument of the method is documented with a constraint
+func hasArgumentChecks(endpoint models.Endpoint, names endpointNames) bool {
+	_, _, checks := requestArguments(endpoint, names)
+	return checks != ""
+}
+
+// generateRequestType renders the request type of the method, holding its query and body
+// arguments, with a Validate method checking them. Only methods with constrained arguments
+// get one.
+func generateRequestType(endpoint models.Endpoint, names endpointNames) string {
+	if names.Request == "" {
+		return ""
+	}
+	params, fieldNames, checks := requestArguments(endpoint, names)
+
+	structDef := fmt.Sprintf("// %s holds the arguments of %s, so they can be validated before it is called\n", names.Request, names.Method)
+	structDef += fmt.Sprintf("type %s struct {\n", names.Request)
+	for i, param := range params {
+		structDef += fmt.Sprintf("\t%s %s", fieldNames[i], param.Type)
+		if param.Description != "" {
+			structDef += " " + formatFieldDescription(param.Description)
+		}
+		structDef += "\n"
+	}
+	structDef += "}\n\n"
+
+	structDef += fmt.Sprintf("// Validate checks %s against the constraints Reddit documents for its arguments\n", names.Request)
+	structDef += fmt.Sprintf("func (r %s) Validate() error {\n", names.Request)
+	return structDef + "\tvar errs ValidationErrors\n" + checks + "\treturn errs.Err()\n}\n\n"
+}
+
+// buildValidation renders the Validate call the method makes on its request before sending
+// it, returning the ValidationErrors when any check fails
+func buildValidation(endpoint models.Endpoint, names endpointNames) string {
+	if names.Request == "" {
+		return ""
+	}
+	params, fieldNames, _ := requestArguments(endpoint, names)
+
+	fields := make([]string, len(params))
+	for i, param := range params {
+		fields[i] = fieldNames[i] + ": " + param.Name
+	}
+	return fmt.Sprintf("\tif err := (%s{%s}).Validate(); err != nil {\n\t\treturn %s, err\n\t}\n", names.Request, strings.Join(fields, ", "), zeroResponse(names))
+}
+
+// generateValidateMethod renders the Validate method of the request struct generated for the
+// object t at path
+func generateValidateMethod(structName, path string, t models.TypeRef, fieldNames []string, names endpointNames) string {
+	var checks string
+	for i, field := range t.Fields {
+		fieldPath := path + "." + field.Name
+		checks += validationChecks(field.Type, field.Constraints, "r."+fieldNames[i], goType(field.Type, fieldPath, names), strconv.Quote(field.Name), fieldPath, names, "\t")
+	}
+
+	method := fmt.Sprintf("// Validate checks %s against the constraints Reddit documents for its fields\n", structName)
+	method += fmt.Sprintf("func (r %s) Validate() error {\n", structName)
+	if checks == "" {
+		return method + "\treturn nil\n}\n\n"
+	}
+	return method + "\tvar errs ValidationErrors\n" + checks + "\treturn errs.Err()\n}\n\n"
+}
@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"reddit-go-api-generator/models"
+	"strings"
+	"testing"
+)
+
+func TestGenerateRequestType(t *testing.T) {
+	maxLimit := 100.0
+	tests := []struct {
+		name     string
+		input    models.Endpoint
+		expected []string
+	}{
+		{
+			name:  "no constraints",
+			input: models.Endpoint{ID: "GET /api/v1/me", Method: "GET", Path: "/api/v1/me"},
+		},
+		{
+			name: "query parameters",
+			input: models.Endpoint{ID: "GET /hot", Method: "GET", Path: "/hot", QueryParams: []models.Parameter{
+				{Name: "after", Type: models.Primitive(models.KindFullname)},
+				{Name: "show", Type: models.Primitive(models.KindString)},
+				{Name: "limit", Type: models.Primitive(models.KindInt), Constraints: &models.Constraints{Max: &maxLimit}},
+			}},
+			expected: []string{
+				"type GetHotRequest struct {\n\tAfter Fullname\n\tShow string\n\tLimit *int\n}\n",
+				"func (r GetHotRequest) Validate() error {",
+				"\terrs.fullname(\"after\", r.After)\n",
+				"\tif r.Limit != nil {\n\t\terrs.max(\"limit\", float64(*r.Limit), 100)\n\t}\n",
+				"\tif err := (GetHotRequest{After: after, Show: show, Limit: limit}).Validate(); err != nil {\n\t\treturn nil, err\n\t}\n",
+			},
+		},
+		{
+			name: "argument named like the method",
+			input: models.Endpoint{ID: "POST /api/check", Method: "POST", Path: "/api/check", Payload: []models.Input{
+				{Name: "validate", Type: models.Primitive(models.KindString), Constraints: &models.Constraints{MaxLength: 10}},
+			}},
+			expected: []string{
+				"type PostCheckRequest struct {\n\tValidate2 string\n}\n",
+				"\terrs.maxLength(\"validate\", r.Validate2, 10)\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		names := resolveNames([]models.Endpoint{test.input})[0]
+		output := generateRequestType(test.input, names) + buildValidation(test.input, names)
+		if len(test.expected) == 0 && output != "" {
+			t.Errorf("For input '%s', expected no validation but got '%s'", test.name, output)
+		}
+		for _, expected := range test.expected {
+			if !strings.Contains(output, expected) {
+				t.Errorf("For input '%s', expected '%s' in '%s'", test.name, expected, output)
+			}
+		}
+	}
+}
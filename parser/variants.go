@@ -0,0 +1,58 @@
+package parser
+
+import (
+	"reddit-go-api-generator/models"
+	"slices"
+)
+
+// subredditParam is the parameter of endpoints documented with an optional [/r/subreddit] prefix
+const subredditParam = "subreddit"
+
+// ExpandVariants returns endpoints with every documented path variant turned into an endpoint
+// of its own, right after the endpoint it belongs to, so each variant gets its own method.
+// Expanding an already expanded list returns it unchanged.
+func ExpandVariants(endpoints []models.Endpoint) []models.Endpoint {
+	var expanded []models.Endpoint
+
+	for _, endpoint := range endpoints {
+		variants := endpoint.Variants
+		endpoint.Variants = nil
+		expanded = append(expanded, endpoint)
+
+		for _, variant := range variants {
+			expanded = append(expanded, variantEndpoint(endpoint, variant))
+		}
+	}
+
+	return expanded
+}
+
+// variantEndpoint copies endpoint for another of its paths, keeping only the URL parameters
+// that still appear in that path
+func variantEndpoint(endpoint models.Endpoint, path string) models.Endpoint {
+	fields := extractDynamicFields(path)
+	if endpoint.OptionalSubreddit {
+		fields = append(fields, subredditParam)
+	}
+
+	var urlParams []string
+	for _, param := range endpoint.URLParams {
+		if slices.Contains(fields, param) {
+			urlParams = append(urlParams, param)
+		}
+	}
+
+	endpoint.ID = endpoint.Method + " " + path
+	endpoint.Path = path
+	endpoint.URLParams = urlParams
+	return endpoint
+}
+
+// CallablePaths returns the paths an endpoint can be called on: its own path, and the same
+// path below /r/{subreddit} when the subreddit is optional
+func CallablePaths(endpoint models.Endpoint) []string {
+	if !endpoint.OptionalSubreddit {
+		return []string{endpoint.Path}
+	}
+	return []string{endpoint.Path, "/r/{" + subredditParam + "}" + endpoint.Path}
+}
@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"fmt"
+	"reddit-go-api-generator/models"
+	"reddit-go-api-generator/report"
+)
+
+// NamingWarnings reports the identifiers generated for endpoints that could not take their
+// preferred name and the parameters left out of a method because another parameter has
+// the same Go name
+func NamingWarnings(endpoints []models.Endpoint) []report.Warning {
+	var warnings []report.Warning
+
+	expanded := ExpandVariants(endpoints)
+	for i, names := range resolveNames(expanded) {
+		for _, renamed := range names.Renamed {
+			warnings = append(warnings, report.Warning{
+				Endpoint: expanded[i].ID,
+				Field:    renamed.Field,
+				Code:     report.CodeRenamedIdentifier,
+				Reason:   fmt.Sprintf("%s is taken, generated %s instead", renamed.Preferred, renamed.Name),
+			})
+		}
+	}
+
+	// A renamed section is reported on the first endpoint documented in it
+	_, sections := sectionInterfaceNames(expanded)
+	for _, renamed := range sections {
+		for _, endpoint := range expanded {
+			if "section "+endpoint.Section == renamed.Field {
+				warnings = append(warnings, report.Warning{
+					Endpoint: endpoint.ID,
+					Field:    renamed.Field,
+					Code:     report.CodeRenamedIdentifier,
+					Reason:   fmt.Sprintf("%s is taken, generated %s instead", renamed.Preferred, renamed.Name),
+				})
+				break
+			}
+		}
+	}
+
+	// Variants share their parameters, so each endpoint is checked once
+	for _, endpoint := range endpoints {
+		_, dropped := methodParams(endpoint, endpointNames{})
+		for _, param := range dropped {
+			warnings = append(warnings, report.Warning{
+				Endpoint: endpoint.ID,
+				Field:    param.WireName,
+				Code:     report.CodeDroppedParam,
+				Reason:   fmt.Sprintf("the %s parameter is left out, another parameter is already named %s", param.In, param.Name),
+			})
+		}
+	}
+
+	return warnings
+}
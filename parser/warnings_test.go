@@ -0,0 +1,43 @@
+package parser
+
+import (
+	"reddit-go-api-generator/models"
+	"reddit-go-api-generator/report"
+	"reflect"
+	"testing"
+)
+
+func TestNamingWarnings(t *testing.T) {
+	endpoints := []models.Endpoint{
+		{ID: "GET /api/v1/me", Method: "GET", Path: "/api/v1/me"},
+		{ID: "GET /api/me", Method: "GET", Path: "/api/me"},
+		{
+			ID: "POST /api/comment", Method: "POST", Path: "/api/comment",
+			Payload:     []models.Input{{Name: "thing_id", Type: models.Primitive(models.KindFullname)}},
+			QueryParams: []models.Parameter{{Name: "thingId", Type: models.Primitive(models.KindString)}},
+		},
+	}
+
+	expected := []report.Warning{
+		{Endpoint: "GET /api/v1/me", Code: report.CodeRenamedIdentifier, Reason: "GetMe is taken, generated GetAPIV1Me instead"},
+		{Endpoint: "POST /api/comment", Field: "thingid", Code: report.CodeDroppedParam, Reason: "the query parameter is left out, another parameter is already named thingID"},
+	}
+	if output := NamingWarnings(endpoints); !reflect.DeepEqual(output, expected) {
+		t.Errorf("Expected '%+v' but got '%+v'", expected, output)
+	}
+}
+
+func TestSectionNamingWarnings(t *testing.T) {
+	endpoints := []models.Endpoint{
+		{ID: "GET /api/info", Method: "GET", Path: "/api/info", Section: "links & comments"},
+		{ID: "POST /api/comment", Method: "POST", Path: "/api/comment", Section: "links and comments"},
+		{ID: "POST /api/del", Method: "POST", Path: "/api/del", Section: "links and comments"},
+	}
+
+	expected := []report.Warning{
+		{Endpoint: "POST /api/comment", Field: "section links and comments", Code: report.CodeRenamedIdentifier, Reason: "LinksAndCommentsAPI is taken, generated LinksAndCommentsAPI2 instead"},
+	}
+	if output := NamingWarnings(endpoints); !reflect.DeepEqual(output, expected) {
+		t.Errorf("Expected '%+v' but got '%+v'", expected, output)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"reddit-go-api-generator/scraper"
+	"strings"
+)
+
+const progressBarWidth = 30
+
+// progressBar renders scraper progress on a single terminal line
+type progressBar struct {
+	w io.Writer
+}
+
+// newProgressReporter returns a reporter drawing to stderr, or nil when stderr is not a
+// terminal or the log level is anything but info (quiet runs hide it, verbose logs would tear it)
+func newProgressReporter(cfg Config) scraper.ProgressReporter {
+	if level, _ := parseLogLevel(cfg.LogLevel); level != slog.LevelInfo {
+		return nil
+	}
+
+	info, err := os.Stderr.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return nil
+	}
+
+	bar := &progressBar{w: os.Stderr}
+	return bar.render
+}
+
+func (b *progressBar) render(p scraper.Progress) {
+	filled := 0
+	if p.Total > 0 {
+		filled = p.Count * progressBarWidth / p.Total
+	}
+
+	fmt.Fprintf(b.w, "\rScraping [%s%s] %d/%d endpoints, %d errors",
+		strings.Repeat("#", filled), strings.Repeat(".", progressBarWidth-filled), p.Count, p.Total, p.Errors)
+
+	if p.Done() {
+		fmt.Fprintln(b.w)
+	}
+}
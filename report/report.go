@@ -0,0 +1,151 @@
+// Package report collects the quality warnings raised while scraping the documentation and
+// naming the generated code, and renders them as JSON or Markdown.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Warning codes, one per kind of problem
+const (
+	// CodeSkippedEndpoint is an endpoint dropped because its method or path could not be read
+	CodeSkippedEndpoint = "skipped-endpoint"
+	// CodeMissingDescription is an endpoint documented without a description
+	CodeMissingDescription = "missing-description"
+	// CodeEmptyPayload is a POST, PATCH or PUT endpoint without any body parameter
+	CodeEmptyPayload = "empty-payload"
+	// CodeUntypedField is a field no type rule matched, generated as interface{}
+	CodeUntypedField = "untyped-field"
+	// CodeDuplicateParam is a parameter documented more than once for an endpoint
+	CodeDuplicateParam = "duplicate-param"
+	// CodeUnparsedJSONModel is a line of a JSON model that could not be read
+	CodeUnparsedJSONModel = "unparsed-json-model"
+	// CodeRenamedIdentifier is a generated identifier that could not take its preferred name
+	CodeRenamedIdentifier = "renamed-identifier"
+	// CodeDroppedParam is a parameter left out of a method because another one has its Go name
+	CodeDroppedParam = "dropped-param"
+)
+
+// Warning is a problem found with one endpoint, or one of its fields
+type Warning struct {
+	// Endpoint is the endpoint ID, e.g. POST /api/comment, or the documentation anchor of
+	// an endpoint that could not be read
+	Endpoint string `json:"endpoint"`
+	// Field is the parameter, field or identifier concerned, empty for the whole endpoint
+	Field  string `json:"field,omitempty"`
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+}
+
+// Collector gathers warnings from concurrent extraction steps. A nil Collector discards them.
+type Collector struct {
+	mu       sync.Mutex
+	warnings []Warning
+}
+
+// Add records warnings
+func (c *Collector) Add(warnings ...Warning) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.warnings = append(c.warnings, warnings...)
+}
+
+// Len returns the number of warnings recorded
+func (c *Collector) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.warnings)
+}
+
+// Warnings returns the recorded warnings sorted by endpoint, field and code, so reports do
+// not depend on the order concurrent steps finished in
+func (c *Collector) Warnings() []Warning {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	warnings := append([]Warning(nil), c.warnings...)
+	c.mu.Unlock()
+
+	sort.SliceStable(warnings, func(i, j int) bool {
+		a, b := warnings[i], warnings[j]
+		if a.Endpoint != b.Endpoint {
+			return a.Endpoint < b.Endpoint
+		}
+		if a.Field != b.Field {
+			return a.Field < b.Field
+		}
+		return a.Code < b.Code
+	})
+	return warnings
+}
+
+// Counts returns the number of warnings per code
+func Counts(warnings []Warning) map[string]int {
+	counts := make(map[string]int)
+	for _, warning := range warnings {
+		counts[warning.Code]++
+	}
+	return counts
+}
+
+// JSON renders warnings with their total and counts per code
+func JSON(warnings []Warning) ([]byte, error) {
+	if warnings == nil {
+		warnings = []Warning{}
+	}
+	content, err := json.MarshalIndent(struct {
+		Total    int            `json:"total"`
+		Counts   map[string]int `json:"counts"`
+		Warnings []Warning      `json:"warnings"`
+	}{len(warnings), Counts(warnings), warnings}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(content, '\n'), nil
+}
+
+// Markdown renders warnings as a summary table of the counts per code followed by a table
+// of every warning
+func Markdown(warnings []Warning) []byte {
+	var b strings.Builder
+	b.WriteString("# Scrape quality report\n\n")
+	if len(warnings) == 0 {
+		b.WriteString("No warnings.\n")
+		return []byte(b.String())
+	}
+
+	counts := Counts(warnings)
+	codes := make([]string, 0, len(counts))
+	for code := range counts {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	fmt.Fprintf(&b, "%d warnings.\n\n| Code | Count |\n| --- | --- |\n", len(warnings))
+	for _, code := range codes {
+		fmt.Fprintf(&b, "| %s | %d |\n", code, counts[code])
+	}
+
+	b.WriteString("\n| Endpoint | Field | Code | Reason |\n| --- | --- | --- | --- |\n")
+	for _, warning := range warnings {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", markdownCell(warning.Endpoint), markdownCell(warning.Field), warning.Code, markdownCell(warning.Reason))
+	}
+	return []byte(b.String())
+}
+
+// markdownCell escapes the characters that would break a table row
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,100 @@
+package report
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCollector(t *testing.T) {
+	var collector Collector
+	var wg sync.WaitGroup
+	for _, warning := range []Warning{
+		{Endpoint: "POST /api/submit", Field: "title", Code: CodeUntypedField},
+		{Endpoint: "GET /hot", Code: CodeMissingDescription},
+		{Endpoint: "POST /api/submit", Code: CodeEmptyPayload},
+	} {
+		wg.Add(1)
+		go func(warning Warning) {
+			defer wg.Done()
+			collector.Add(warning)
+		}(warning)
+	}
+	wg.Wait()
+
+	expected := []Warning{
+		{Endpoint: "GET /hot", Code: CodeMissingDescription},
+		{Endpoint: "POST /api/submit", Code: CodeEmptyPayload},
+		{Endpoint: "POST /api/submit", Field: "title", Code: CodeUntypedField},
+	}
+	if output := collector.Warnings(); !reflect.DeepEqual(output, expected) {
+		t.Errorf("Expected '%+v' but got '%+v'", expected, output)
+	}
+
+	var discarded *Collector
+	discarded.Add(Warning{Endpoint: "GET /hot"})
+	if discarded.Len() != 0 || discarded.Warnings() != nil {
+		t.Errorf("Expected a nil collector to discard warnings")
+	}
+}
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		input    []Warning
+		expected string
+	}{
+		{nil, `{"total":0,"counts":{},"warnings":[]}`},
+		{
+			[]Warning{{Endpoint: "GET /hot", Field: "limit", Code: CodeDuplicateParam, Reason: "documented 2 times"}},
+			`{"total":1,"counts":{"duplicate-param":1},"warnings":[{"endpoint":"GET /hot","field":"limit","code":"duplicate-param","reason":"documented 2 times"}]}`,
+		},
+	}
+
+	for _, test := range tests {
+		content, err := JSON(test.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var output, expected any
+		if err := json.Unmarshal(content, &output); err != nil {
+			t.Fatal(err)
+		}
+		_ = json.Unmarshal([]byte(test.expected), &expected)
+		if !reflect.DeepEqual(output, expected) {
+			t.Errorf("For input '%+v', expected '%s' but got '%s'", test.input, test.expected, content)
+		}
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	tests := []struct {
+		input    []Warning
+		expected []string
+	}{
+		{nil, []string{"No warnings."}},
+		{
+			[]Warning{
+				{Endpoint: "GET /hot", Field: "g", Code: CodeUntypedField, Reason: "one of (a|b)\nno rule"},
+				{Endpoint: "GET /new", Code: CodeMissingDescription, Reason: "no description"},
+			},
+			[]string{
+				"2 warnings.",
+				"| missing-description | 1 |",
+				"| untyped-field | 1 |",
+				`| GET /hot | g | untyped-field | one of (a\|b) no rule |`,
+				"| GET /new |  | missing-description | no description |",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		output := string(Markdown(test.input))
+		for _, line := range test.expected {
+			if !strings.Contains(output, line) {
+				t.Errorf("For input '%+v', expected '%s' in '%s'", test.input, line, output)
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package scraper
+
+import (
+	"reddit-go-api-generator/models"
+	"regexp"
+	"strconv"
+)
+
+// numberPattern captures an integer or decimal bound
+const numberPattern = `(-?\d+(?:\.\d+)?)`
+
+var (
+	// "a string no longer than 300 characters", "up to 100 characters"
+	maxLengthPattern = regexp.MustCompile(`(?i)(?:no longer than|up to|at most)\s+(\d+)\s+char`)
+	// "at least 3 characters"
+	minLengthPattern = regexp.MustCompile(`(?i)at least\s+(\d+)\s+char`)
+	// "an integer between 1 and 100", "between 3 and 21 characters"
+	betweenPattern = regexp.MustCompile(`(?i)between\s+` + numberPattern + `\s+and\s+` + numberPattern + `(\s+char)?`)
+	// "(default: 25, maximum: 100)", "maximum: 100 characters"
+	maximumPattern = regexp.MustCompile(`(?i)\bmax(?:imum)?:?\s+` + numberPattern + `(\s+char)?`)
+	minimumPattern = regexp.MustCompile(`(?i)\bmin(?:imum)?:?\s+` + numberPattern + `(\s+char)?`)
+)
+
+// extractConstraints reads the length and range limits written in a field description.
+// It returns nil when the description documents none.
+func extractConstraints(description string) *models.Constraints {
+	var c models.Constraints
+
+	if m := maxLengthPattern.FindStringSubmatch(description); m != nil {
+		c.MaxLength, _ = strconv.Atoi(m[1])
+	}
+	if m := minLengthPattern.FindStringSubmatch(description); m != nil {
+		c.MinLength, _ = strconv.Atoi(m[1])
+	}
+	if m := betweenPattern.FindStringSubmatch(description); m != nil {
+		setBound(&c, m[1], m[3] != "", true)
+		setBound(&c, m[2], m[3] != "", false)
+	}
+	if m := maximumPattern.FindStringSubmatch(description); m != nil {
+		setBound(&c, m[1], m[2] != "", false)
+	}
+	if m := minimumPattern.FindStringSubmatch(description); m != nil {
+		setBound(&c, m[1], m[2] != "", true)
+	}
+
+	if c == (models.Constraints{}) {
+		return nil
+	}
+	return &c
+}
+
+// setBound records value as the lower or upper limit of a length or of the value itself,
+// keeping a limit that was already found
+func setBound(c *models.Constraints, value string, length, lower bool) {
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return
+	}
+
+	switch {
+	case length && lower && c.MinLength == 0:
+		c.MinLength = int(parsed)
+	case length && !lower && c.MaxLength == 0:
+		c.MaxLength = int(parsed)
+	case !length && lower && c.Min == nil:
+		c.Min = &parsed
+	case !length && !lower && c.Max == nil:
+		c.Max = &parsed
+	}
+}
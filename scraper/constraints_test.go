@@ -0,0 +1,33 @@
+package scraper
+
+import (
+	"reddit-go-api-generator/models"
+	"reflect"
+	"testing"
+)
+
+func TestExtractConstraints(t *testing.T) {
+	bound := func(value float64) *float64 { return &value }
+
+	tests := []struct {
+		input    string
+		expected *models.Constraints
+	}{
+		{"a string no longer than 300 characters", &models.Constraints{MaxLength: 300}},
+		{"title: up to 300 characters", &models.Constraints{MaxLength: 300}},
+		{"at least 3 characters", &models.Constraints{MinLength: 3}},
+		{"between 3 and 21 characters", &models.Constraints{MinLength: 3, MaxLength: 21}},
+		{"an integer between 1 and 100", &models.Constraints{Min: bound(1), Max: bound(100)}},
+		{"the maximum number of items desired (default: 25, maximum: 100)", &models.Constraints{Max: bound(100)}},
+		{"a float between -1.5 and 1.5", &models.Constraints{Min: bound(-1.5), Max: bound(1.5)}},
+		{"a positive integer (default: 0)", nil},
+		{"raw markdown text", nil},
+	}
+
+	for _, test := range tests {
+		output := extractConstraints(test.input)
+		if !reflect.DeepEqual(output, test.expected) {
+			t.Errorf("For input '%s', expected '%+v' but got '%+v'", test.input, test.expected, output)
+		}
+	}
+}
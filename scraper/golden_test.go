@@ -0,0 +1,112 @@
+package scraper
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reddit-go-api-generator/models"
+	"reddit-go-api-generator/parser"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden with the current output")
+
+// TestGoldenGeneration runs every fixture in testdata/endpoints through processEndpoint
+// and the generator, and compares the generated Go code with testdata/golden.
+func TestGoldenGeneration(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "endpoints", "*.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatal("no fixtures found in testdata/endpoints")
+	}
+
+	for _, fixture := range fixtures {
+		name := strings.TrimSuffix(filepath.Base(fixture), ".html")
+
+		t.Run(name, func(t *testing.T) {
+			endpoints := endpointsFromFixture(t, fixture)
+
+			// The first generated chunk is the static SDK helpers, which are not worth a golden copy
+			functions := parser.GenerateGoFunctions(endpoints, parser.Options{})[1:]
+
+			assertGolden(t, filepath.Join("testdata", "golden", name+".golden"), strings.Join(functions, "\n\n"))
+		})
+	}
+}
+
+// endpointsFromFixture extracts every div.endpoint in an HTML fixture
+func endpointsFromFixture(t *testing.T, path string) []models.Endpoint {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	doc, err := goquery.NewDocumentFromReader(file)
+	if err != nil {
+		t.Fatalf("could not parse %s: %v", path, err)
+	}
+
+	var endpoints []models.Endpoint
+	doc.Find("div.endpoint").Each(func(i int, sel *goquery.Selection) {
+		endpoint, err := processEndpoint(sel, nil, nil, nil)
+		if err != nil {
+			t.Fatalf("could not process endpoint %d in %s: %v", i, path, err)
+		}
+		endpoints = append(endpoints, endpoint)
+	})
+
+	return endpoints
+}
+
+// assertGolden compares got with the golden file at path, rewriting it when -update is set
+func assertGolden(t *testing.T, path string, got string) {
+	t.Helper()
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read golden file (run go test ./scraper -update to create it): %v", err)
+	}
+
+	if string(expected) != got {
+		t.Errorf("generated code does not match %s (run go test ./scraper -update to accept it)\n%s", path, lineDiff(string(expected), got))
+	}
+}
+
+// lineDiff describes the first line where expected and got disagree
+func lineDiff(expected, got string) string {
+	expectedLines := strings.Split(expected, "\n")
+	gotLines := strings.Split(got, "\n")
+
+	for i := 0; i < len(expectedLines) || i < len(gotLines); i++ {
+		var e, g string
+		if i < len(expectedLines) {
+			e = expectedLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if e != g {
+			return fmt.Sprintf("line %d:\n  expected: %s\n  got:      %s", i+1, e, g)
+		}
+	}
+	return ""
+}
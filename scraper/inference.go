@@ -0,0 +1,152 @@
+package scraper
+
+import (
+	"reddit-go-api-generator/models"
+	"reddit-go-api-generator/parser"
+	"regexp"
+	"strings"
+)
+
+// TypeRule infers the type of a documented field from its name and description
+type TypeRule struct {
+	// Name is recorded on every field the rule types, see models.Field.TypeRule
+	Name string
+	// Match returns the type and true when the rule applies
+	Match func(name, description string) (models.TypeRef, bool)
+}
+
+// KeywordRule types fields whose description contains any of keywords, ignoring case
+func KeywordRule(ruleName string, t models.TypeRef, keywords ...string) TypeRule {
+	return TypeRule{Name: ruleName, Match: func(_, description string) (models.TypeRef, bool) {
+		description = strings.ToLower(description)
+		for _, keyword := range keywords {
+			if strings.Contains(description, keyword) {
+				return t, true
+			}
+		}
+		return models.TypeRef{}, false
+	}}
+}
+
+// RegexRule types fields whose description matches pattern
+func RegexRule(ruleName, pattern string, t models.TypeRef) TypeRule {
+	re := regexp.MustCompile(pattern)
+	return TypeRule{Name: ruleName, Match: func(_, description string) (models.TypeRef, bool) {
+		return t, re.MatchString(description)
+	}}
+}
+
+// ParamNameRule types fields whose name matches pattern, whatever their description says
+func ParamNameRule(ruleName, pattern string, t models.TypeRef) TypeRule {
+	re := regexp.MustCompile(pattern)
+	return TypeRule{Name: ruleName, Match: func(name, _ string) (models.TypeRef, bool) {
+		return t, re.MatchString(name)
+	}}
+}
+
+// enumRule reads the values of descriptions such as "one of (`hot`, `new`)"
+var enumRule = TypeRule{Name: "enum", Match: func(_, description string) (models.TypeRef, bool) {
+	if !strings.Contains(strings.ToLower(description), "one of") {
+		return models.TypeRef{}, false
+	}
+
+	// Fix original desc for the one-off case where the descriptino contains "one of (`,left,right`)"
+	// This is just broken, it should be changed to one of (left, right)
+	description = parser.FormatOneOfEnum(description)
+
+	// Extract the content between parentheses as the enum values
+	start := strings.Index(description, "one of (")
+	end := strings.Index(description, ")")
+	if start == -1 || end == -1 || end <= start {
+		return models.TypeRef{}, false
+	}
+
+	var values []string
+	for _, value := range strings.Split(strings.ReplaceAll(description[start+8:end], "`", ""), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return models.EnumOf(values...), true
+}}
+
+// DefaultTypeRules are tried in order, the first match wins. Names Reddit always returns with
+// the same mixed type come first, then description rules. Other parameter names only decide
+// when the description says nothing useful.
+var DefaultTypeRules = []TypeRule{
+	ParamNameRule("name-edited", `^edited$`, models.Primitive(models.KindBoolOrTimestamp)),
+	ParamNameRule("name-timestamp", `^(created|created_utc|\w+_utc)$`, models.Primitive(models.KindTimestamp)),
+	RegexRule("bool-or-timestamp", `(?i)\bfalse\b.*\btimestamp\b|\btimestamp\b.*\bfalse\b`, models.Primitive(models.KindBoolOrTimestamp)),
+	RegexRule("string-or-number", `(?i)\b(string|integer|number) or (a |an )?(string|integer|number)\b`, models.Primitive(models.KindStringOrNumber)),
+	KeywordRule("timestamp", models.Primitive(models.KindTimestamp), "timestamp", "epoch"),
+	KeywordRule("boolean", models.Primitive(models.KindBool), "boolean"),
+	KeywordRule("integer", models.Primitive(models.KindInt), "integer"),
+	KeywordRule("string", models.Primitive(models.KindString), "string"),
+	KeywordRule("url", models.Primitive(models.KindString), "valid url"),
+	KeywordRule("email", models.Primitive(models.KindString), "a valid email"),
+	KeywordRule("fullname", models.Primitive(models.KindFullname), "fullname"),
+	enumRule,
+	KeywordRule("expand", models.Primitive(models.KindBool), "expand"),
+	KeywordRule("text", models.Primitive(models.KindString), "alphanumeric", "characters", "comma-separated", "markdown"),
+	KeywordRule("username", models.Primitive(models.KindString), "username"),
+	RegexRule("color", `(?i)\bhex colou?r\b`, models.Primitive(models.KindString)),
+	RegexRule("base36", `(?i)\bbase ?36\b`, models.Primitive(models.KindString)),
+	ParamNameRule("name-count", `^(limit|count|sr_limit)$`, models.Primitive(models.KindInt)),
+	ParamNameRule("name-id", `(^|_)(id|name)$`, models.Primitive(models.KindString)),
+}
+
+// inferType applies rules, DefaultTypeRules when nil, and returns the type with the name of
+// the rule that decided it. Fields no rule matches are untyped and get an empty rule name.
+func inferType(rules []TypeRule, name, description string) (models.TypeRef, string) {
+	if rules == nil {
+		rules = DefaultTypeRules
+	}
+	for _, rule := range rules {
+		if t, ok := rule.Match(name, description); ok {
+			return t, rule.Name
+		}
+	}
+	return models.TypeRef{}, ""
+}
+
+// inferField builds a field typed by rules, with the constraints its description documents
+func inferField(rules []TypeRule, name, description string) models.Field {
+	t, rule := inferType(rules, name, description)
+	return models.Field{Name: name, Description: description, Type: t, TypeRule: rule, Constraints: extractConstraints(description)}
+}
+
+// UntypedField is a documented field no type rule matched
+type UntypedField struct {
+	Endpoint    string `json:"endpoint"`
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// UntypedFields lists the payload, query and response fields of endpoints that fell back to
+// an untyped result, nested fields included, so new rules can be written for them
+func UntypedFields(endpoints []models.Endpoint) []UntypedField {
+	var untyped []UntypedField
+
+	var visit func(id, prefix string, fields []models.Field)
+	visit = func(id, prefix string, fields []models.Field) {
+		for _, field := range fields {
+			t := field.Type
+			for t.Kind == models.KindArray && t.Elem != nil {
+				t = *t.Elem
+			}
+			switch {
+			case t.Kind == models.KindObject:
+				visit(id, prefix+field.Name+".", t.Fields)
+			case t.Kind == models.KindAny && field.TypeRule == "":
+				untyped = append(untyped, UntypedField{Endpoint: id, Field: prefix + field.Name, Description: field.Description})
+			}
+		}
+	}
+
+	for _, endpoint := range endpoints {
+		visit(endpoint.ID, "", endpoint.Payload)
+		visit(endpoint.ID, "", endpoint.QueryParams)
+		visit(endpoint.ID, "", endpoint.Response)
+	}
+	return untyped
+}
@@ -0,0 +1,83 @@
+package scraper
+
+import (
+	"reddit-go-api-generator/models"
+	"reflect"
+	"testing"
+)
+
+func TestInferType(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		expected    models.TypeRef
+		rule        string
+	}{
+		{"return_rtjson", "boolean value", models.Primitive(models.KindBool), "boolean"},
+		{"count", "a positive integer (default: 0)", models.Primitive(models.KindInt), "integer"},
+		{"show", "(optional) the string all", models.Primitive(models.KindString), "string"},
+		{"after", "fullname of a thing", models.Primitive(models.KindFullname), "fullname"},
+		{"g", "one of (`GLOBAL`, `US`)", models.EnumOf("GLOBAL", "US"), "enum"},
+		{"t", "one of (`,hour,day`)", models.EnumOf("hour", "day"), "enum"},
+		{"sr_detail", "(optional) expand subreddits", models.Primitive(models.KindBool), "expand"},
+		{"text", "raw markdown text", models.Primitive(models.KindString), "text"},
+		{"name", "A valid, existing reddit username", models.Primitive(models.KindString), "username"},
+		{"headerColor", "a 6-digit rgb hex color, e.g. `#AABBCC`", models.Primitive(models.KindString), "color"},
+		{"conversation_id", "A valid conversation id encoded in base36.", models.Primitive(models.KindString), "base36"},
+		{"limit", "the maximum number of items desired (default: 25, maximum: 100)", models.Primitive(models.KindInt), "name-count"},
+		{"sr_name", "", models.Primitive(models.KindString), "name-id"},
+		{"created_utc", "", models.Primitive(models.KindTimestamp), "name-timestamp"},
+		{"edited", "", models.Primitive(models.KindBoolOrTimestamp), "name-edited"},
+		{"approved_at", "false, or the timestamp of the approval", models.Primitive(models.KindBoolOrTimestamp), "bool-or-timestamp"},
+		{"since", "a UNIX timestamp", models.Primitive(models.KindTimestamp), "timestamp"},
+		{"score", "an integer or a string", models.Primitive(models.KindStringOrNumber), "string-or-number"},
+		{"uh", "a modhash", models.TypeRef{}, ""},
+	}
+
+	for _, test := range tests {
+		output, rule := inferType(nil, test.name, test.description)
+		if !reflect.DeepEqual(output, test.expected) || rule != test.rule {
+			t.Errorf("For input '%s', expected '%s' from rule '%s' but got '%s' from rule '%s'", test.description, test.expected, test.rule, output, rule)
+		}
+	}
+}
+
+func TestInferTypeCustomRules(t *testing.T) {
+	rules := append([]TypeRule{
+		ParamNameRule("modhash", `^uh$`, models.Primitive(models.KindString)),
+	}, DefaultTypeRules...)
+
+	if output, rule := inferType(rules, "uh", "a modhash"); output.Kind != models.KindString || rule != "modhash" {
+		t.Errorf("expected the custom rule to type uh, got '%s' from rule '%s'", output, rule)
+	}
+	if output, rule := inferType(rules, "count", "a positive integer"); output.Kind != models.KindInt || rule != "integer" {
+		t.Errorf("expected the default rules to still apply, got '%s' from rule '%s'", output, rule)
+	}
+}
+
+func TestUntypedFields(t *testing.T) {
+	endpoints := []models.Endpoint{
+		{
+			ID: "POST /api/widget",
+			Payload: []models.Input{
+				{Name: "kind", Type: models.EnumOf("image"), TypeRule: "enum"},
+				{Name: "data", Type: models.ArrayOf(models.ObjectOf(
+					models.Field{Name: "height", Type: models.Primitive(models.KindInt), TypeRule: "integer"},
+					models.Field{Name: "linkUrl", Description: "somewhere"},
+				))},
+			},
+		},
+		{
+			ID:          "GET /hot",
+			QueryParams: []models.Parameter{{Name: "uh", Description: "a modhash"}},
+		},
+	}
+
+	expected := []UntypedField{
+		{Endpoint: "POST /api/widget", Field: "data.linkUrl", Description: "somewhere"},
+		{Endpoint: "GET /hot", Field: "uh", Description: "a modhash"},
+	}
+	if output := UntypedFields(endpoints); !reflect.DeepEqual(output, expected) {
+		t.Errorf("expected %+v but got %+v", expected, output)
+	}
+}
@@ -0,0 +1,141 @@
+package scraper
+
+import (
+	"fmt"
+	"reddit-go-api-generator/models"
+	"reddit-go-api-generator/parser"
+	"reddit-go-api-generator/report"
+	"strings"
+)
+
+// parseJSONModel reads the pseudo-JSON blocks Reddit documents request bodies with, e.g.
+//
+//	{
+//	  "name": A valid, existing reddit username,
+//	  "styles": {
+//	    "headerColor": a 6-digit rgb hex color, e.g. `#AABBCC`,
+//	  },
+//	  "subreddits": [
+//	    subreddit name,
+//	    ...
+//	  ],
+//	}
+//
+// The values are prose rather than JSON, so the block is read line by line, lines that make
+// no sense are skipped and reported to warn, and the values are typed with rules. A block
+// describing an array is returned as a single input named json, which the generator sends
+// as the whole body.
+func parseJSONModel(block string, rules []TypeRule, warn warnFunc) []models.Input {
+	var root models.TypeRef
+	var stack []*models.TypeRef
+
+	for _, line := range strings.Split(block, "\n") {
+		line = strings.TrimSuffix(strings.TrimSpace(line), ",")
+		if line == "" || line == "..." || line == "…" {
+			continue
+		}
+
+		var top *models.TypeRef
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+
+		switch {
+		case strings.HasPrefix(line, "}") || strings.HasPrefix(line, "]"):
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+
+		case line == "{" || line == "[":
+			container := &root
+			if top != nil {
+				if top.Kind != models.KindArray || top.Elem != nil {
+					// Skip objects without a key and further examples of an array element
+					stack = append(stack, &models.TypeRef{})
+					continue
+				}
+				top.Elem = &models.TypeRef{}
+				container = top.Elem
+			}
+			container.Kind = containerKind(line)
+			stack = append(stack, container)
+
+		default:
+			if top != nil && top.Kind == models.KindArray {
+				if top.Elem == nil {
+					elem, _ := inferType(rules, "", line)
+					top.Elem = &elem
+				}
+				continue
+			}
+
+			name, value, ok := splitJSONModelLine(line)
+			if !ok {
+				warn.warn("", report.CodeUnparsedJSONModel, fmt.Sprintf("skipped the line %q", line))
+				continue
+			}
+			if top == nil {
+				// Tolerate blocks without the surrounding braces
+				root.Kind = models.KindObject
+				stack = append(stack, &root)
+				top = &root
+			}
+			if top.Kind != models.KindObject {
+				continue
+			}
+
+			field := inferField(rules, name, value)
+			if value == "{" || value == "[" || value == "{}" || value == "[]" {
+				field = models.Field{Name: name, Type: models.Primitive(containerKind(value))}
+			}
+			top.Fields = append(top.Fields, field)
+			if value == "{" || value == "[" {
+				stack = append(stack, &top.Fields[len(top.Fields)-1].Type)
+			}
+		}
+	}
+
+	switch root.Kind {
+	case models.KindObject:
+		return root.Fields
+	case models.KindArray:
+		return []models.Input{{Name: "json", Type: root}}
+	}
+	return nil
+}
+
+// splitJSONModelLine splits a `"name": description` line. Names may be unquoted.
+func splitJSONModelLine(line string) (name, value string, ok bool) {
+	var rest string
+	if strings.HasPrefix(line, `"`) {
+		end := strings.Index(line[1:], `"`)
+		if end == -1 {
+			return "", "", false
+		}
+		name, rest = line[1:end+1], line[end+2:]
+	} else {
+		colon := strings.Index(line, ":")
+		if colon == -1 || strings.ContainsAny(line[:colon], " \t") {
+			return "", "", false
+		}
+		name, rest = line[:colon], line[colon:]
+	}
+
+	rest = strings.TrimSpace(rest)
+	if !strings.HasPrefix(rest, ":") {
+		return "", "", false
+	}
+
+	name = parser.RemoveInvalidCharacters(name)
+	if name == "" {
+		return "", "", false
+	}
+	return name, strings.TrimSpace(rest[1:]), true
+}
+
+func containerKind(opening string) models.TypeKind {
+	if strings.HasPrefix(opening, "[") {
+		return models.KindArray
+	}
+	return models.KindObject
+}
@@ -0,0 +1,90 @@
+package scraper
+
+import (
+	"reddit-go-api-generator/models"
+	"reflect"
+	"testing"
+)
+
+func TestParseJSONModel(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []models.Input
+	}{
+		{
+			name: "flat",
+			input: `{
+  "name": A valid, existing reddit username,
+  "note": a string no longer than 300 characters,
+}`,
+			expected: []models.Input{
+				{Name: "name", Description: "A valid, existing reddit username", Type: models.Primitive(models.KindString), TypeRule: "username"},
+				{Name: "note", Description: "a string no longer than 300 characters", Type: models.Primitive(models.KindString), TypeRule: "string", Constraints: &models.Constraints{MaxLength: 300}},
+			},
+		},
+		{
+			name: "nested",
+			input: `{
+  "data": [
+    {
+      "height": an integer,
+      "url": a valid URL of a reddit-hosted image,
+    },
+    ...
+  ],
+  "kind": one of (` + "`image`" + `),
+  "styles": {
+    "headerColor": a 6-digit rgb hex color, e.g. ` + "`#AABBCC`" + `,
+  },
+  "subreddits": [
+    subreddit name,
+    ...
+  ],
+  "empty": {},
+}`,
+			expected: []models.Input{
+				{Name: "data", Type: models.ArrayOf(models.ObjectOf(
+					models.Field{Name: "height", Description: "an integer", Type: models.Primitive(models.KindInt), TypeRule: "integer"},
+					models.Field{Name: "url", Description: "a valid URL of a reddit-hosted image", Type: models.Primitive(models.KindString), TypeRule: "url"},
+				))},
+				{Name: "kind", Description: "one of (`image`)", Type: models.EnumOf("image"), TypeRule: "enum"},
+				{Name: "styles", Type: models.ObjectOf(
+					models.Field{Name: "headerColor", Description: "a 6-digit rgb hex color, e.g. `#AABBCC`", Type: models.Primitive(models.KindString), TypeRule: "color"},
+				)},
+				{Name: "subreddits", Type: models.ArrayOf(models.TypeRef{})},
+				{Name: "empty", Type: models.Primitive(models.KindObject)},
+			},
+		},
+		{
+			name: "array body",
+			input: `[
+  a string no longer than 1000 characters,
+  ...
+]`,
+			expected: []models.Input{
+				{Name: "json", Type: models.ArrayOf(models.Primitive(models.KindString))},
+			},
+		},
+		{
+			name:  "no braces and unquoted names",
+			input: "title: a string no longer than 300 characters\n('user',): a valid username",
+			expected: []models.Input{
+				{Name: "title", Description: "a string no longer than 300 characters", Type: models.Primitive(models.KindString), TypeRule: "string", Constraints: &models.Constraints{MaxLength: 300}},
+				{Name: "user", Description: "a valid username", Type: models.Primitive(models.KindString), TypeRule: "username"},
+			},
+		},
+		{
+			name:     "prose",
+			input:    "This endpoint takes no JSON body.",
+			expected: nil,
+		},
+	}
+
+	for _, test := range tests {
+		output := parseJSONModel(test.input, nil, nil)
+		if !reflect.DeepEqual(output, test.expected) {
+			t.Errorf("For input '%s', expected %+v but got %+v", test.name, test.expected, output)
+		}
+	}
+}
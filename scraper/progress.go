@@ -0,0 +1,69 @@
+package scraper
+
+import "sync"
+
+// Progress is a snapshot of how far a scrape has got
+type Progress struct {
+	// Count is the number of endpoints processed so far, including failed ones
+	Count int
+	// Total is the number of endpoints that will be processed
+	Total int
+	// Errors is the number of endpoints that could not be extracted
+	Errors int
+}
+
+// Done reports whether every endpoint has been processed
+func (p Progress) Done() bool {
+	return p.Count >= p.Total
+}
+
+// ProgressReporter receives a Progress snapshot after every processed endpoint
+type ProgressReporter func(Progress)
+
+// tracker serializes the callbacks in Options so they never run concurrently
+type tracker struct {
+	mu       sync.Mutex
+	opts     Options
+	progress Progress
+}
+
+func newTracker(opts Options, total int) *tracker {
+	t := &tracker{opts: opts, progress: Progress{Total: total}}
+	if opts.OnProgress != nil {
+		opts.OnProgress(t.progress)
+	}
+	return t
+}
+
+func (t *tracker) targeted(id string) {
+	if t.opts.OnEndpointTargeted == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.opts.OnEndpointTargeted(id)
+}
+
+// finished records a processed endpoint. id is empty when the endpoint could not be identified.
+func (t *tracker) finished(id string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.progress.Count++
+	if err != nil {
+		t.progress.Errors++
+	} else if t.opts.OnEndpointProcessed != nil {
+		t.opts.OnEndpointProcessed(id)
+	}
+
+	if t.opts.OnProgress != nil {
+		t.opts.OnProgress(t.progress)
+	}
+}
+
+// snapshot returns the current progress
+func (t *tracker) snapshot() Progress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.progress
+}
@@ -0,0 +1,81 @@
+package scraper
+
+import "reddit-go-api-generator/models"
+
+// KnownResponseRule is the TypeRule of the fields in knownResponses. The API page never
+// documents responses, so their types are declared here rather than inferred.
+const KnownResponseRule = "known-response"
+
+// known declares a field of a known response
+func known(name, description string, t models.TypeRef) models.Output {
+	return models.Output{Name: name, Description: description, Type: t, TypeRule: KnownResponseRule}
+}
+
+var (
+	stringType  = models.Primitive(models.KindString)
+	intType     = models.Primitive(models.KindInt)
+	boolType    = models.Primitive(models.KindBool)
+	fullname    = models.Primitive(models.KindFullname)
+	timestamp   = models.Primitive(models.KindTimestamp)
+	editedType  = models.Primitive(models.KindBoolOrTimestamp)
+	thingFields = []models.Output{
+		known("id", "the base36 ID of the thing", stringType),
+		known("name", "the fullname of the thing", fullname),
+		known("author", "the username of the author", stringType),
+		known("subreddit", "the name of the subreddit", stringType),
+		known("title", "the title of a link", stringType),
+		known("body", "the markdown text of a comment", stringType),
+		known("permalink", "the path of the thing on reddit", stringType),
+		known("url", "the URL the link points to", stringType),
+		known("score", "the score", intType),
+		known("num_comments", "the number of comments on a link", intType),
+		known("over_18", "whether the thing is NSFW", boolType),
+		known("created_utc", "when the thing was created", timestamp),
+		known("edited", "false, or when the thing was last edited", editedType),
+	}
+)
+
+// listingFields describe a Listing, a page of things with the fullnames of its neighbours
+var listingFields = []models.Output{
+	known("kind", "the string Listing", stringType),
+	known("data", "the page", models.ObjectOf(
+		known("after", "fullname of the thing after this page", fullname),
+		known("before", "fullname of the thing before this page", fullname),
+		known("dist", "the number of children", intType),
+		known("children", "the things on the page", models.ArrayOf(models.ObjectOf(
+			known("kind", "the kind of the thing, such as t1 or t3", stringType),
+			known("data", "the fields of the thing", models.ObjectOf(thingFields...)),
+		))),
+	)),
+}
+
+// knownResponses are the response bodies of endpoints by ID. Variants of an endpoint share
+// its response, every other endpoint has none.
+var knownResponses = map[string][]models.Output{
+	"GET /api/v1/me": {
+		known("id", "the base36 ID of the account", stringType),
+		known("name", "the username of the account", stringType),
+		known("created_utc", "when the account was created", timestamp),
+		known("link_karma", "the link karma", intType),
+		known("comment_karma", "the comment karma", intType),
+		known("total_karma", "the total karma", intType),
+		known("inbox_count", "the number of unread messages", intType),
+		known("has_verified_email", "whether the email is verified", boolType),
+		known("is_gold", "whether the account has premium", boolType),
+		known("is_mod", "whether the account moderates a subreddit", boolType),
+		known("over_18", "whether the account sees NSFW content", boolType),
+	},
+	"GET /api/info": listingFields,
+	"GET /best":     listingFields,
+	"GET /hot":      listingFields,
+	"GET /new":      listingFields,
+	"GET /rising":   listingFields,
+	"GET /search":   listingFields,
+	"GET /{sort}":   listingFields,
+}
+
+// knownResponse returns the response declared in knownResponses for the endpoint, or nil
+// when its response is unknown
+func knownResponse(id string) []models.Output {
+	return knownResponses[id]
+}
@@ -0,0 +1,59 @@
+package scraper
+
+import (
+	"reddit-go-api-generator/models"
+	"strings"
+	"testing"
+)
+
+func TestKnownResponseTypes(t *testing.T) {
+	endpoint := endpointsFromFixture(t, "testdata/endpoints/listing.html")[0]
+
+	tests := []struct {
+		path     string
+		expected models.TypeKind
+	}{
+		{"kind", models.KindString},
+		{"data.after", models.KindFullname},
+		{"data.dist", models.KindInt},
+		{"data.children", models.KindArray},
+		{"data.children[].data.name", models.KindFullname},
+		{"data.children[].data.score", models.KindInt},
+		{"data.children[].data.created_utc", models.KindTimestamp},
+		{"data.children[].data.edited", models.KindBoolOrTimestamp},
+	}
+
+	for _, test := range tests {
+		output, ok := responseField(endpoint.Response, test.path)
+		if !ok || output.Type.Kind != test.expected {
+			t.Errorf("For input '%s', expected '%s' but got '%s'", test.path, test.expected, output.Type.Kind)
+		}
+		if output.TypeRule != KnownResponseRule {
+			t.Errorf("For input '%s', expected the rule '%s' but got '%s'", test.path, KnownResponseRule, output.TypeRule)
+		}
+	}
+
+	if fields := UntypedFields([]models.Endpoint{endpoint}); len(fields) != 0 {
+		t.Errorf("Expected every response field to be typed, got %+v", fields)
+	}
+}
+
+// responseField finds the field at path, e.g. data.children[].data.edited
+func responseField(fields []models.Field, path string) (models.Field, bool) {
+	name, rest, nested := strings.Cut(path, ".")
+	name, isElem := strings.CutSuffix(name, "[]")
+	for _, field := range fields {
+		if field.Name != name {
+			continue
+		}
+		if !nested {
+			return field, true
+		}
+		t := field.Type
+		if isElem && t.Elem != nil {
+			t = *t.Elem
+		}
+		return responseField(t.Fields, rest)
+	}
+	return models.Field{}, false
+}
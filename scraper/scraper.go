@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"github.com/PuerkitoBio/goquery"
 	"github.com/gocolly/colly"
-	"log"
+	"html"
+	"log/slog"
 	"net/http"
 	"reddit-go-api-generator/models"
 	"reddit-go-api-generator/parser"
+	"reddit-go-api-generator/report"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -20,10 +23,38 @@ const (
 	RedditAPIUrl = "https://www.reddit.com/dev/api"
 )
 
+// Options controls a scrape
+type Options struct {
+	// URL is the documentation page to scrape, RedditAPIUrl when empty
+	URL string
+	// Limit caps the number of endpoints processed. Zero or less processes all of them.
+	Limit int
+	// OnEndpointTargeted is called with the endpoint ID once its method and path are known
+	OnEndpointTargeted func(id string)
+	// OnEndpointProcessed is called with the endpoint ID after it has been fully extracted
+	OnEndpointProcessed func(id string)
+	// OnProgress is called after every processed endpoint, successful or not
+	OnProgress ProgressReporter
+	// TypeRules infer the type of every documented field, DefaultTypeRules when nil
+	TypeRules []TypeRule
+	// Warnings collects the problems found while extracting endpoints. It may be nil.
+	Warnings *report.Collector
+}
+
+// ScrapeRedditAPI scrapes at most limit endpoints (all of them when limit is zero),
+// calling onEndpointTargeted and onEndpointProcessed with each endpoint ID
 func ScrapeRedditAPI(limit int, onEndpointTargeted, onEndpointProcessed func(string)) ([]models.Endpoint, error) {
+	return Scrape(Options{
+		Limit:               limit,
+		OnEndpointTargeted:  onEndpointTargeted,
+		OnEndpointProcessed: onEndpointProcessed,
+	})
+}
+
+// Scrape collects the endpoints documented on the Reddit API page
+func Scrape(opts Options) ([]models.Endpoint, error) {
 	c := colly.NewCollector()
 	c.SetRequestTimeout(1 * time.Second) // Adjust as needed
-	var mu sync.Mutex                    // For safe access to results slice
 	var wg sync.WaitGroup
 
 	c.WithTransport(&http.Transport{
@@ -42,449 +73,329 @@ func ScrapeRedditAPI(limit int, onEndpointTargeted, onEndpointProcessed func(str
 	c.OnRequest(func(r *colly.Request) {
 		select {
 		case <-ctx.Done():
-			log.Println("Request canceled for:", r.URL.String())
+			slog.Debug("Request canceled", "url", r.URL.String())
 			r.Abort()
 		default:
 		}
 	})
 
-	var elements []*colly.HTMLElement
-	results := make([]models.Endpoint, 0)
+	var elements []*goquery.Selection
 
 	c.OnHTML("div.endpoint", func(e *colly.HTMLElement) {
-		elements = append(elements, e) // Collect all matching elements
+		elements = append(elements, e.DOM) // Collect all matching elements
 	})
 
 	c.OnError(func(r *colly.Response, err error) {
-		log.Printf("Error visiting %s: %v", r.Request.URL, err)
+		slog.Warn("Error visiting page", "url", r.Request.URL.String(), "error", err)
 	})
 
 	// Start collection and wait for it to finish
-	err := c.Visit(RedditAPIUrl)
+	url := opts.URL
+	if url == "" {
+		url = RedditAPIUrl
+	}
+
+	err := c.Visit(url)
 	if err != nil {
-		log.Printf("Error during visit: %v", err)
+		slog.Error("Error during visit", "error", err)
 		return nil, err
 	}
 
-	log.Println("Visit completed")
+	slog.Debug("Visit completed")
+
+	if opts.Limit > 0 && len(elements) > opts.Limit {
+		elements = elements[:opts.Limit]
+	}
 
-	// Split the collected elements into 4 parts
-	splittedElements := splitIntoParts(elements, 4)
+	progress := newTracker(opts, len(elements))
+
+	// Every element has a fixed slot so results keep their document order
+	// no matter which goroutine finishes first
+	processed := make([]models.Endpoint, len(elements))
+	succeeded := make([]bool, len(elements))
+
+	// Split the collected element indexes into 4 parts
+	splittedIndexes := splitIntoParts(len(elements), 4)
 
 	// Process each segment concurrently
-	for _, segment := range splittedElements {
+	for _, segment := range splittedIndexes {
 		wg.Add(1)
-		go func(segment []*colly.HTMLElement) {
+		go func(segment []int) {
 			defer wg.Done()
-			var localResults []models.Endpoint
-			for _, e := range segment {
-				endpoint := processEndpoint(e)
-				localResults = append(localResults, endpoint)
+			for _, i := range segment {
+				e := elements[i]
+				endpoint, err := processEndpoint(e, opts.TypeRules, progress.targeted, opts.Warnings)
+				progress.finished(endpoint.ID, err)
+				if err != nil {
+					slog.Warn("Skipping endpoint", "id", e.AttrOr("id", ""), "error", err)
+					opts.Warnings.Add(report.Warning{Endpoint: e.AttrOr("id", ""), Code: report.CodeSkippedEndpoint, Reason: err.Error()})
+					continue
+				}
+				processed[i] = endpoint
+				succeeded[i] = true
 			}
-
-			// Lock to safely append to the shared results slice
-			mu.Lock()
-			results = append(results, localResults...)
-			mu.Unlock()
 		}(segment)
 	}
 
 	c.OnScraped(func(r *colly.Response) {
-		log.Println("Scraping finished for", r.Request.URL)
+		slog.Debug("Scraping finished", "url", r.Request.URL.String())
 	})
 
 	// Wait for all goroutines to complete
 	wg.Wait()
 
+	results := make([]models.Endpoint, 0, len(elements))
+	for i, endpoint := range processed {
+		if succeeded[i] {
+			results = append(results, endpoint)
+		}
+	}
+
 	//os.Exit(0)
-	log.Printf("Scraping completed. Found %d endpoints", len(results))
-	log.Printf("Number of Goroutines: %d", runtime.NumGoroutine())
+	slog.Info("Scraping completed", "endpoints", len(results), "errors", progress.snapshot().Errors)
+	slog.Debug("Scraper goroutines", "count", runtime.NumGoroutine())
 
 	return results, nil
 }
 
-func processEndpoint(e *colly.HTMLElement,
-
-//onEndpointTargetted func(string), onEndpointProcessed func(string)
-
-) models.Endpoint {
+// processEndpoint extracts a single endpoint, typing its fields with rules. onTargeted is
+// called with the endpoint ID as soon as the method and path are known. Problems with the
+// extracted endpoint are recorded in warnings, which may be nil.
+func processEndpoint(e *goquery.Selection, rules []TypeRule, onTargeted func(string), warnings *report.Collector) (models.Endpoint, error) {
 	start := time.Now()
-	log.Println("Processing started")
+	slog.Debug("Processing started")
 
-	method := e.ChildText("h3 span.method")
-	log.Printf("Method extracted: %s (Time: %v)", method, time.Since(start))
+	method := childText(e, "h3 span.method")
+	slog.Debug("Method extracted", "method", method, "elapsed", time.Since(start))
 
-	path := extractDynamicPath(e)
-	log.Printf("Path extracted: %s (Time: %v)", path, time.Since(start))
+	path, optionalSubreddit := extractDynamicPath(e)
+	slog.Debug("Path extracted", "path", path, "optionalSubreddit", optionalSubreddit, "elapsed", time.Since(start))
 
 	// Continue logging other operations in the same way...
-	description := e.ChildText("div.md p")
-	log.Printf("Description extracted: %s (Time: %v)", description, time.Since(start))
+	description := childText(e, "div.md p")
+	slog.Debug("Description extracted", "description", description, "elapsed", time.Since(start))
 
 	// Further down the function, after each major step
-	log.Printf("Endpoint processed in %v", time.Since(start))
+	slog.Debug("Endpoint processed", "elapsed", time.Since(start))
+
+	if method == "" || path == "" {
+		return models.Endpoint{}, fmt.Errorf("endpoint is missing its method or path (method %q, path %q)", method, path)
+	}
 
 	id := method + " " + path
 
-	//onEndpointTargetted(id)
+	if onTargeted != nil {
+		onTargeted(id)
+	}
+
+	warn := warnFunc(func(field, code, reason string) {
+		warnings.Add(report.Warning{Endpoint: id, Field: field, Code: code, Reason: reason})
+	})
 
 	if description == "" {
-		description = "No description available"
+		description = noDescription
 	}
 
 	urlParams := extractURLParams(e)
-	log.Printf("urlParams processed in %v", time.Since(start))
+	slog.Debug("urlParams processed", "elapsed", time.Since(start))
 
-	payload := extractPayload(e)
-	log.Printf("payload processed in %v", time.Since(start))
+	payload := extractPayload(e, rules, warn)
+	slog.Debug("payload processed", "elapsed", time.Since(start))
 
-	newPayload, response := extractPayloadOrResponse(e, method)
-
-	queryParams := extractQueryParams(e)
+	newPayload, queryParams := extractParameters(e, method, path, rules)
 
+	// A JSON model describes the whole body, the table rows next to it are path parameters
 	finalPayload := payload
-
-	if len(newPayload) > len(payload) {
+	if len(finalPayload) == 0 {
 		finalPayload = newPayload
 	}
 
 	endpoint := models.Endpoint{
-		ID:          id,
-		Method:      method,
-		Path:        path,
-		Description: description,
-		URLParams:   urlParams,
-		Payload:     finalPayload,
-		Response:    response,
-		QueryParams: queryParams,
+		ID:                id,
+		Method:            method,
+		Path:              path,
+		OptionalSubreddit: optionalSubreddit,
+		Variants:          extractVariants(e, path),
+		Section:           extractSection(e),
+		Anchor:            e.AttrOr("id", ""),
+		Scopes:            extractScopes(e),
+		Description:       description,
+		URLParams:         urlParams,
+		Payload:           finalPayload,
+		QueryParams:       queryParams,
+		Response:          knownResponse(id),
 	}
 
-	//onEndpointProcessed(id)
-
-	return endpoint
+	warnings.Add(EndpointWarnings(endpoint)...)
+	return endpoint, nil
 }
 
 // Extract URL parameters from placeholders in the path
-func extractURLParams(e *colly.HTMLElement) []string {
+func extractURLParams(e *goquery.Selection) []string {
 	var urlParams []string
-	e.ForEach("h3 em.placeholder", func(_ int, em *colly.HTMLElement) {
-		urlParams = append(urlParams, em.Text)
+	e.Find("h3 em.placeholder").Each(func(_ int, em *goquery.Selection) {
+		urlParams = append(urlParams, em.Text())
 	})
 
 	return urlParams
 }
 
-// Extract payload parameters for POST/PATCH requests
-// func extractPayload(e *colly.HTMLElement) []Input {
-// 	var inputs []Input
-// 	e.ForEach("table.parameters tr.json-model", func(_ int, tr *colly.HTMLElement) {
-// 		payload := tr.ChildText("td pre code")
-// 		if payload != "" {
-// 			lines := strings.Split(payload, "\n")
-// 			for _, line := range lines {
-// 				line = strings.TrimSpace(line)
-// 				if line == "{" || line == "}" || line == "" {
-// 					continue
-// 				}
-
-// 				name, inputType, description := parsePayloadLine(line)
-// 				input := Input{
-// 					Name:        name,
-// 					Description: description,
-// 					Type:        inputType,
-// 				}
-// 				inputs = append(inputs, input)
-// 			}
-// 		}
-// 	})
-// 	return inputs
-// }
-
-// Extract payload parameters when indicated in the HTML structure
-func extractPayload(e *colly.HTMLElement) []models.Input {
+// Extract the request body documented by an "expects JSON data of this format" block
+func extractPayload(e *goquery.Selection, rules []TypeRule, warn warnFunc) []models.Input {
 	var inputs []models.Input
 
-	// Check if there's a specific indication that this table is for JSON payload
-	e.ForEach("table.parameters tr", func(_ int, tr *colly.HTMLElement) {
-		header := tr.ChildText("th")
-
-		if strings.Contains(header, "header") {
-			return
-		}
-
-		// Look for exact text that indicates payload structure
-		if strings.Contains(header, "expects JSON data of this format") {
-
-			codeBlock := tr.DOM.Find("td pre code")
-
-			if codeBlock != nil {
-				lines := strings.Split(codeBlock.Text(), "\n")
-				for _, line := range lines {
-					line = strings.TrimSpace(line)
-					if line == "{" || line == "}" || line == "" {
-						continue
-					}
-
-					name, inputType, description := parsePayloadLine(line)
-					if name != "" {
-						input := models.Input{
-							Name:        name,
-							Description: description,
-							Type:        inputType,
-						}
-						inputs = append(inputs, input)
-					}
-				}
-			}
+	e.Find("table.parameters tr").Each(func(_ int, tr *goquery.Selection) {
+		if isJSONModel(tr) {
+			inputs = append(inputs, parseJSONModel(tr.Find("td pre code").Text(), rules, warn)...)
 		}
 	})
 
 	return inputs
 }
 
-// Parses a line of payload and returns the name, type, and description
-func parsePayloadLine(line string) (name, inputType, description string) {
-	parts := strings.SplitN(line, ":", 2)
-	if len(parts) < 2 {
-		return "", "", ""
-	}
-
-	name = parser.RemoveInvalidCharacters(name)
-	name = strings.TrimSpace(strings.Trim(parts[0], `"`))
-	description = strings.TrimSpace(parts[1])
-
-	inputType = determineType(description)
-
-	return name, inputType, description
+// isJSONModel reports whether a parameters row holds a JSON model instead of a parameter
+func isJSONModel(tr *goquery.Selection) bool {
+	return tr.HasClass("json-model") || strings.Contains(childText(tr, "th"), "expects JSON data of this format")
 }
 
-func extractPayloadOrResponse(e *colly.HTMLElement, method string) ([]models.Input, []models.Output) {
+// Extract the parameter table. Rows of POST, PATCH and PUT endpoints are sent in the body,
+// rows of every other method in the query string. Path placeholders are skipped.
+func extractParameters(e *goquery.Selection, method, path string, rules []TypeRule) ([]models.Input, []models.Parameter) {
 	var inputs []models.Input
-	var outputs []models.Output
-	isPayload := false
-
-	// Determine if the table is likely a payload or a response
-	switch method {
-	case "POST", "PATCH", "PUT":
-		// For these methods, if the scope is related to mod actions or submissions, it's a payload
-		// if strings.Contains(oauthScope, "modposts") || strings.Contains(oauthScope, "submit") {
-		isPayload = true
-		// }
-	case "GET":
-		// For GET methods, if the scope is "read," it's likely a response
-		// if strings.Contains(oauthScope, "read") {
-		isPayload = false
-		// }
-	}
+	var queryParams []models.Parameter
 
-	// Extract information based on the determined type
-	e.ForEach("table.parameters tbody tr", func(_ int, tr *colly.HTMLElement) {
-		paramName := tr.ChildText("th")
-		paramDesc := tr.ChildText("td p")
+	e.Find("table.parameters tbody tr").Each(func(_ int, tr *goquery.Selection) {
+		paramName := childText(tr, "th")
+		paramDesc := childText(tr, "td p")
 
-		// Skip header fields
-		if strings.Contains(strings.ToLower(paramName), "header") {
+		// Skip header fields and JSON models, which extractPayload reads
+		if strings.Contains(strings.ToLower(paramName), "header") || isJSONModel(tr) {
 			return
 		}
 
-		if isPayload {
-			inputType := determineType(paramDesc)
-			input := models.Input{
-				Name:        paramName,
-				Description: paramDesc,
-				Type:        inputType,
-			}
-			inputs = append(inputs, input)
-		} else {
-			outputType := determineType(paramDesc)
-			output := models.Output{
-				Name:        paramName,
-				Description: paramDesc,
-				Type:        outputType,
-			}
-			outputs = append(outputs, output)
+		field := inferField(rules, paramName, paramDesc)
+
+		if isPayload(method) {
+			inputs = append(inputs, field)
+		} else if paramName != "" && !strings.Contains(path, "{"+paramName+"}") {
+			queryParams = append(queryParams, field)
 		}
 	})
 
-	return inputs, outputs
+	return inputs, queryParams
 }
 
-// Extract response parameters when they don't indicate a payload structure
-// func extractResponse(e *colly.HTMLElement) []RedditAPIField {
-// 	var response []Output
-// 	e.ForEach("table.parameters tr", func(_ int, tr *colly.HTMLElement) {
-// 		header := tr.ChildText("th")
-// 		// Ensure we are not parsing a table meant for payload
-// 		if strings.Contains(header, "expects JSON data of this format") {
-// 			// Skip tables that indicate they are payload
-// 			return
-// 		}
-
-// 		// Extract response parameters
-// 		paramName := tr.ChildText("th")
-// 		paramDesc := tr.ChildText("td p")
-// 		paramType := determineType(paramDesc)
-
-// 		response = append(response, Output{
-// 			Name:        paramName,
-// 			Description: paramDesc,
-// 			Type:        paramType,
-// 		})
-// 	})
-// 	return response
-// }
-
+// isPayload reports whether the parameters of method are sent in the request body
 func isPayload(method string) bool {
-	isPayload := true
-
-	// Determine if the table is likely a payload or a response
 	switch method {
 	case "POST", "PATCH", "PUT":
-		// For these methods, if the scope is related to mod actions or submissions, it's a payload
-		// if strings.Contains(oauthScope, "modposts") || strings.Contains(oauthScope, "submit") {
-		isPayload = true
-		// }
-	case "GET":
-		// For GET methods, if the scope is "read," it's likely a response
-		// if strings.Contains(oauthScope, "read") {
-		isPayload = false
-		// }
+		return true
 	}
-
-	return isPayload
+	return false
 }
 
-// Extract query parameters if present
-func extractQueryParams(e *colly.HTMLElement) []models.Parameter {
-	var queryParams []models.Parameter
-	e.ForEach("table.parameters tbody tr", func(_ int, tr *colly.HTMLElement) {
-		if tr.ChildText("th") == "after" || tr.ChildText("th") == "before" || tr.ChildText("th") == "count" || tr.ChildText("th") == "limit" {
-			paramName := tr.ChildText("th")
-			paramDesc := tr.ChildText("td p")
-			paramType := determineType(paramDesc)
-
-			queryParams = append(queryParams, models.Parameter{
-				Name:        paramName,
-				Description: paramDesc,
-				Type:        paramType,
-			})
+// Extract the OAuth scopes listed next to the endpoint path
+func extractScopes(e *goquery.Selection) []string {
+	var scopes []string
+	e.Find("h3 span.oauth-scope-list span.oauth-scope").Each(func(_ int, scope *goquery.Selection) {
+		if text := strings.TrimSpace(scope.Text()); text != "" {
+			scopes = append(scopes, text)
 		}
 	})
-	return queryParams
+	return scopes
 }
 
-// Determine the type of a property based on its description
-func determineType(description string) string {
-	originalDesc := description
-	description = strings.ToLower(description)
-
-	switch {
-	case strings.Contains(description, "boolean"):
-		return "bool"
-	//case strings.Contains(description, "json"):
-	//	return "json"
-	case strings.Contains(description, "integer"):
-		return "int"
-	case strings.Contains(description, "string"):
-		return "string"
-	case strings.Contains(description, "valid url"):
-		return "string"
-	case strings.Contains(description, "a valid email"):
-		return "string"
-	case strings.Contains(description, "fullname"):
-		return "string"
-	case strings.Contains(description, "one of"):
-		// Fix original desc for the one-off case where the descriptino contains "one of (`,left,right`)"
-		// This is just broken, it should be changed to one of (left, right)
-		originalDesc := parser.FormatOneOfEnum(originalDesc)
-
-		// Extract the content between parentheses as the enum values
-		start := strings.Index(originalDesc, "one of (")
-		end := strings.Index(originalDesc, ")")
-		if start != -1 && end != -1 && end > start {
-			options := originalDesc[start+8 : end]
-
-			options = strings.ReplaceAll(options, "`", "")
-
-			// Return formatted enum type with options
-			return fmt.Sprintf("enum(%s)", options)
+// Extract the documentation section (e.g. "links & comments") the endpoint is listed under
+func extractSection(e *goquery.Selection) string {
+	if section := e.Closest("div.section"); section.Length() > 0 {
+		if heading := strings.TrimSpace(section.ChildrenFiltered("h2").First().Text()); heading != "" {
+			return heading
 		}
-	case strings.Contains(description, "expand"):
-		return "bool"
-	case strings.Contains(description, "alphanumeric"):
-		return "string"
-	case strings.Contains(description, "characters"):
-		return "string"
-	// Retain the original cases that may not have been included before
-	case strings.Contains(description, "comma-separated"):
-		return "string"
-	case strings.Contains(description, "optional") && strings.Contains(description, "boolean"):
-		return "bool"
-	case strings.Contains(description, "optional") && strings.Contains(description, "integer"):
-		return "int"
 	}
 
-	return "interface{}"
+	// Fall back to the closest heading before the endpoint
+	return strings.TrimSpace(e.PrevAllFiltered("h2").First().Text())
 }
 
-// Extract path from the h3 element, excluding oauth-scope-list and other elements
-func extractCleanPath(e *colly.HTMLElement) string {
-	h3 := e.DOM.Find("h3")
+// Extract dynamic fields (e.g., {where}, [/r/subreddit]) from the path. The second result
+// reports whether the path is documented with an optional [/r/subreddit] prefix.
+func extractDynamicPath(e *goquery.Selection) (string, bool) {
+	// Work on a copy so the scope list stays in the document for extractScopes
+	h3 := e.Find("h3").Clone()
 
-	// Remove any oauth-scope-list and api-badge elements from the h3
+	// Remove oauth-scope-list and other non-path elements
 	h3.Find("span.oauth-scope-list").Remove()
 	h3.Find("a").Remove()
+	h3.Find("span.method").Remove()
 
-	cleanPath := strings.TrimSpace(h3.Text())
-	cleanPath = strings.TrimSpace(strings.Replace(cleanPath, e.ChildText("h3 span.method"), "", 1))
-
-	return cleanPath
+	return parsePath(h3)
 }
 
-// Extract dynamic fields (e.g., {where}, [r/subreddit]) from the path
-func extractDynamicPath(e *colly.HTMLElement) string {
-	h3 := e.DOM.Find("h3")
-
-	// Remove oauth-scope-list and other non-path elements
-	h3.Find("span.oauth-scope-list").Remove()
-	h3.Find("a").Remove()
+// Extract the other documented paths of the endpoint, e.g. /about/banned for /about/{where}.
+// Paths equal to the main one are skipped.
+func extractVariants(e *goquery.Selection, path string) []string {
+	var variants []string
+	e.Find("ul.uri-variants li").Each(func(_ int, li *goquery.Selection) {
+		variant, _ := parsePath(li.Clone())
+		if variant != "" && variant != path && !slices.Contains(variants, variant) {
+			variants = append(variants, variant)
+		}
+	})
+	return variants
+}
 
-	cleanPath := strings.TrimSpace(h3.Text())
-	cleanPath = strings.TrimSpace(strings.Replace(cleanPath, e.ChildText("h3 span.method"), "", 1))
+// parsePath reads a path from sel, turning <em class="placeholder"> elements into {name}
+// fields and stripping an optional [/r/subreddit] prefix, which is reported separately
+func parsePath(sel *goquery.Selection) (string, bool) {
+	sel.Find("em.placeholder").Each(func(_ int, el *goquery.Selection) {
+		el.ReplaceWithHtml("{" + html.EscapeString(strings.TrimSpace(el.Text())) + "}")
+	})
 
-	// Replace <em class="placeholder">...</em> elements with dynamic field format
-	h3.Find("em.placeholder").Each(func(_ int, el *goquery.Selection) {
-		placeholder := el.Text()
+	cleanPath := strings.TrimSpace(sel.Text())
+	// Variants are listed as "→ /path"
+	cleanPath = strings.TrimSpace(strings.TrimPrefix(cleanPath, "→"))
 
-		// Replace [r/subreddit] pattern with "r/{subreddit}"
-		if placeholder == "subreddit" && strings.Contains(cleanPath, "[r/"+placeholder+"]") {
-			cleanPath = strings.ReplaceAll(cleanPath, "[r/"+placeholder+"]", "r/{subreddit}")
-		} else {
-			// Replace other placeholders like "where" with "{where}"
-			cleanPath = strings.ReplaceAll(cleanPath, placeholder, "{"+placeholder+"}")
+	optionalSubreddit := false
+	for _, prefix := range []string{"[/r/{subreddit}]", "[r/{subreddit}]"} {
+		if strings.HasPrefix(cleanPath, prefix) {
+			cleanPath = strings.TrimPrefix(cleanPath, prefix)
+			optionalSubreddit = true
+			break
 		}
-	})
+	}
+	if optionalSubreddit && !strings.HasPrefix(cleanPath, "/") {
+		cleanPath = "/" + cleanPath
+	}
 
 	// Remove any remaining brackets in the path
 	cleanPath = strings.ReplaceAll(cleanPath, "[", "")
 	cleanPath = strings.ReplaceAll(cleanPath, "]", "")
 	cleanPath = parser.CleanColonPath(cleanPath)
 
-	return cleanPath
+	return cleanPath, optionalSubreddit
 }
 
-// Split a slice of elements into `n` parts
-func splitIntoParts(elements []*colly.HTMLElement, n int) [][]*colly.HTMLElement {
-	length := len(elements)
+// childText returns the trimmed text of the elements matching selector below sel
+func childText(sel *goquery.Selection, selector string) string {
+	return strings.TrimSpace(sel.Find(selector).Text())
+}
+
+// Split the indexes 0..length-1 into `n` contiguous parts
+func splitIntoParts(length int, n int) [][]int {
 	partSize := (length + n - 1) / n // This ensures we split the slice evenly
 
-	var parts [][]*colly.HTMLElement
+	var parts [][]int
 	for i := 0; i < length; i += partSize {
 		end := i + partSize
 		if end > length {
 			end = length
 		}
-		parts = append(parts, elements[i:end])
+
+		part := make([]int, 0, end-i)
+		for j := i; j < end; j++ {
+			part = append(part, j)
+		}
+		parts = append(parts, part)
 	}
 	return parts
 }
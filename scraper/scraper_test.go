@@ -0,0 +1,170 @@
+package scraper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reddit-go-api-generator/models"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// newDocServer serves the saved documentation page so scrapes never touch reddit.com
+func newDocServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/dev/api" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		http.ServeFile(w, r, "testdata/reddit_api.html")
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestScrapeKeepsDocumentOrder(t *testing.T) {
+	server := newDocServer(t)
+
+	var mu sync.Mutex
+	var targeted, processed []string
+	var last Progress
+
+	endpoints, err := Scrape(Options{
+		URL: server.URL + "/dev/api",
+		OnEndpointTargeted: func(id string) {
+			mu.Lock()
+			defer mu.Unlock()
+			targeted = append(targeted, id)
+		},
+		OnEndpointProcessed: func(id string) {
+			mu.Lock()
+			defer mu.Unlock()
+			processed = append(processed, id)
+		},
+		OnProgress: func(p Progress) { last = p },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"GET /api/v1/me",
+		"POST /api/comment",
+		"GET /hot",
+		"DELETE /api/mod/conversations/{conversation_id}/highlight",
+		"PUT /api/v1/me/friends/{username}",
+	}
+
+	var ids []string
+	for _, endpoint := range endpoints {
+		ids = append(ids, endpoint.ID)
+	}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected endpoints %v but got %v", expected, ids)
+	}
+
+	var sections []string
+	for _, endpoint := range endpoints {
+		sections = append(sections, endpoint.Section)
+	}
+	expectedSections := []string{"account", "links & comments", "listings", "new modmail", "users"}
+	if !reflect.DeepEqual(sections, expectedSections) {
+		t.Errorf("expected sections %v but got %v", expectedSections, sections)
+	}
+
+	if me := endpoints[0]; me.Anchor != "GET_api_v1_me" || !reflect.DeepEqual(me.Scopes, []string{"identity"}) {
+		t.Errorf("expected anchor and scopes of GET /api/v1/me, got %q and %v", me.Anchor, me.Scopes)
+	}
+
+	if len(targeted) != len(expected) || len(processed) != len(expected) {
+		t.Errorf("expected %d targeted and processed callbacks, got %d and %d", len(expected), len(targeted), len(processed))
+	}
+
+	if want := (Progress{Count: 6, Total: 6, Errors: 1}); last != want {
+		t.Errorf("expected final progress %+v but got %+v", want, last)
+	}
+}
+
+func TestProcessEndpointPathVariants(t *testing.T) {
+	tests := []struct {
+		fixture           string
+		path              string
+		optionalSubreddit bool
+		variants          []string
+	}{
+		{"listing", "/hot", true, nil},
+		{"about", "/about/{where}", true, []string{"/about/banned", "/about/muted", "/about/wikibanned"}},
+		{"friend", "/api/v1/me/friends/{username}", false, nil},
+		{"modmail", "/api/mod/conversations/{conversation_id}/highlight", false, nil},
+	}
+
+	for _, test := range tests {
+		endpoint := endpointsFromFixture(t, "testdata/endpoints/"+test.fixture+".html")[0]
+		if endpoint.Path != test.path || endpoint.OptionalSubreddit != test.optionalSubreddit {
+			t.Errorf("For input '%s', expected '%s' (optional subreddit %t) but got '%s' (%t)",
+				test.fixture, test.path, test.optionalSubreddit, endpoint.Path, endpoint.OptionalSubreddit)
+		}
+		if !reflect.DeepEqual(endpoint.Variants, test.variants) {
+			t.Errorf("For input '%s', expected variants %v but got %v", test.fixture, test.variants, endpoint.Variants)
+		}
+	}
+}
+
+func TestProcessEndpointParameters(t *testing.T) {
+	tests := []struct {
+		fixture     string
+		payload     []string
+		queryParams []string
+		response    []string
+	}{
+		{"listing", nil, []string{"g", "after", "before", "count", "limit", "show", "sr_detail"}, []string{"kind", "data"}},
+		{"friend", []string{"name", "note"}, nil, nil},
+		{"modmail", nil, nil, nil},
+	}
+
+	names := func(fields []models.Field) []string {
+		var names []string
+		for _, field := range fields {
+			names = append(names, field.Name)
+		}
+		return names
+	}
+
+	for _, test := range tests {
+		endpoint := endpointsFromFixture(t, "testdata/endpoints/"+test.fixture+".html")[0]
+		if payload := names(endpoint.Payload); !reflect.DeepEqual(payload, test.payload) {
+			t.Errorf("For input '%s', expected payload %v but got %v", test.fixture, test.payload, payload)
+		}
+		if queryParams := names(endpoint.QueryParams); !reflect.DeepEqual(queryParams, test.queryParams) {
+			t.Errorf("For input '%s', expected query parameters %v but got %v", test.fixture, test.queryParams, queryParams)
+		}
+		if response := names(endpoint.Response); !reflect.DeepEqual(response, test.response) {
+			t.Errorf("For input '%s', expected response fields %v but got %v", test.fixture, test.response, response)
+		}
+	}
+}
+
+func TestScrapeHonorsLimit(t *testing.T) {
+	server := newDocServer(t)
+
+	endpoints, err := Scrape(Options{URL: server.URL + "/dev/api", Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(endpoints) != 2 || endpoints[0].ID != "GET /api/v1/me" || endpoints[1].ID != "POST /api/comment" {
+		t.Errorf("expected the first two endpoints, got %+v", endpoints)
+	}
+}
+
+func TestScrapeReportsHTTPErrors(t *testing.T) {
+	server := newDocServer(t)
+
+	if _, err := Scrape(Options{URL: server.URL + "/missing"}); err == nil {
+		t.Error("expected an error for a missing documentation page")
+	}
+}
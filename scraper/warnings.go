@@ -0,0 +1,59 @@
+package scraper
+
+import (
+	"fmt"
+	"reddit-go-api-generator/models"
+	"reddit-go-api-generator/report"
+)
+
+// noDescription replaces the description of endpoints documented without one
+const noDescription = "No description available"
+
+// warnFunc records a warning about a field of the endpoint being extracted. It may be nil.
+type warnFunc func(field, code, reason string)
+
+func (w warnFunc) warn(field, code, reason string) {
+	if w != nil {
+		w(field, code, reason)
+	}
+}
+
+// EndpointWarnings checks an extracted endpoint for a missing description, an empty payload,
+// untyped fields and parameters documented more than once. It works on endpoints loaded
+// from a JSON file as well as on freshly scraped ones.
+func EndpointWarnings(endpoint models.Endpoint) []report.Warning {
+	var warnings []report.Warning
+	add := func(field, code, reason string) {
+		warnings = append(warnings, report.Warning{Endpoint: endpoint.ID, Field: field, Code: code, Reason: reason})
+	}
+
+	if endpoint.Description == "" || endpoint.Description == noDescription {
+		add("", report.CodeMissingDescription, "the endpoint is documented without a description")
+	}
+
+	if isPayload(endpoint.Method) && len(endpoint.Payload) == 0 {
+		add("", report.CodeEmptyPayload, fmt.Sprintf("%s endpoint documents no body parameters", endpoint.Method))
+	}
+
+	for _, untyped := range UntypedFields([]models.Endpoint{endpoint}) {
+		add(untyped.Field, report.CodeUntypedField, fmt.Sprintf("no type rule matched %q, generated as interface{}", untyped.Description))
+	}
+
+	counts := make(map[string]int)
+	var order []string
+	for _, fields := range [][]models.Field{endpoint.Payload, endpoint.QueryParams} {
+		for _, field := range fields {
+			if counts[field.Name] == 0 {
+				order = append(order, field.Name)
+			}
+			counts[field.Name]++
+		}
+	}
+	for _, name := range order {
+		if counts[name] > 1 {
+			add(name, report.CodeDuplicateParam, fmt.Sprintf("documented %d times", counts[name]))
+		}
+	}
+
+	return warnings
+}
@@ -0,0 +1,85 @@
+package scraper
+
+import (
+	"reddit-go-api-generator/models"
+	"reddit-go-api-generator/report"
+	"reflect"
+	"testing"
+)
+
+func TestEndpointWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    models.Endpoint
+		expected []report.Warning
+	}{
+		{
+			name: "complete",
+			input: models.Endpoint{ID: "POST /api/comment", Method: "POST", Description: "Submit a new comment.", Payload: []models.Input{
+				{Name: "text", Type: models.Primitive(models.KindString), TypeRule: "string"},
+			}},
+		},
+		{
+			name:  "empty payload without description",
+			input: models.Endpoint{ID: "POST /api/hide", Method: "POST", Description: noDescription},
+			expected: []report.Warning{
+				{Endpoint: "POST /api/hide", Code: report.CodeMissingDescription, Reason: "the endpoint is documented without a description"},
+				{Endpoint: "POST /api/hide", Code: report.CodeEmptyPayload, Reason: "POST endpoint documents no body parameters"},
+			},
+		},
+		{
+			name: "untyped and duplicated query parameters",
+			input: models.Endpoint{ID: "GET /hot", Method: "GET", Description: "A listing.", QueryParams: []models.Parameter{
+				{Name: "g", Description: "a region"},
+				{Name: "limit", Type: models.Primitive(models.KindInt), TypeRule: "name-count"},
+				{Name: "limit", Type: models.Primitive(models.KindInt), TypeRule: "name-count"},
+			}},
+			expected: []report.Warning{
+				{Endpoint: "GET /hot", Field: "g", Code: report.CodeUntypedField, Reason: `no type rule matched "a region", generated as interface{}`},
+				{Endpoint: "GET /hot", Field: "limit", Code: report.CodeDuplicateParam, Reason: "documented 2 times"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		output := EndpointWarnings(test.input)
+		if !reflect.DeepEqual(output, test.expected) {
+			t.Errorf("For input '%s', expected '%+v' but got '%+v'", test.name, test.expected, output)
+		}
+	}
+}
+
+func TestParseJSONModelWarnings(t *testing.T) {
+	var skipped []string
+	warn := warnFunc(func(field, code, reason string) {
+		if code == report.CodeUnparsedJSONModel {
+			skipped = append(skipped, reason)
+		}
+	})
+
+	parseJSONModel(`{
+  "name": a string,
+  this line is prose,
+}`, nil, warn)
+
+	expected := []string{`skipped the line "this line is prose"`}
+	if !reflect.DeepEqual(skipped, expected) {
+		t.Errorf("Expected '%v' but got '%v'", expected, skipped)
+	}
+}
+
+func TestScrapeCollectsWarnings(t *testing.T) {
+	server := newDocServer(t)
+
+	var warnings report.Collector
+	if _, err := Scrape(Options{URL: server.URL + "/dev/api", Warnings: &warnings}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []report.Warning{
+		{Endpoint: "broken", Code: report.CodeSkippedEndpoint, Reason: `endpoint is missing its method or path (method "", path "")`},
+	}
+	if output := warnings.Warnings(); !reflect.DeepEqual(output, expected) {
+		t.Errorf("Expected '%+v' but got '%+v'", expected, output)
+	}
+}
@@ -0,0 +1,203 @@
+package writer
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// generatedHeader matches the standard Go marker for machine-generated files
+// (see https://go.dev/s/generatedcode), or the same marker in an HTML comment
+// for generated Markdown and HTML pages.
+var generatedHeader = regexp.MustCompile(`^(// Code generated .* DO NOT EDIT\.|<!-- Code generated .* DO NOT EDIT\. -->)$`)
+
+// ErrUserFile is returned when a generated file would replace a file that was
+// not produced by the generator.
+var ErrUserFile = errors.New("file was not generated by reddigo")
+
+// File is a single generated file, relative to the output directory
+type File struct {
+	Name    string
+	Content []byte
+}
+
+// Options controls how generated files are written
+type Options struct {
+	// Clean removes previously generated files that are no longer part of the output.
+	// Only .go files starting with Header in the directories files are written to are removed.
+	Clean bool
+	// Header is the generated-code line the files of this generator start with, e.g.
+	// "// Code generated by reddigo-generator. DO NOT EDIT.". Existing files are only replaced,
+	// or removed by Clean, when they start with it.
+	Header string
+}
+
+// Result lists what happened to each file during a Write
+type Result struct {
+	Written   []string
+	Unchanged []string
+	Removed   []string
+}
+
+// Write places files into dir without touching anything the generator does not own.
+// Existing files are only replaced when they start with opts.Header, so files generated by
+// other tools are left alone too, and every file is written to a temporary file first and
+// renamed into place.
+func Write(dir string, files []File, opts Options) (Result, error) {
+	var result Result
+
+	if opts.Header == "" {
+		return result, errors.New("writing needs the header of the generated files")
+	}
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return result, fmt.Errorf("could not create output directory: %w", err)
+	}
+
+	// Check every target before writing anything so a conflict leaves the directory untouched
+	for _, file := range files {
+		target := filepath.Join(dir, file.Name)
+
+		existing, err := os.ReadFile(target)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return result, err
+		}
+		if firstLine(existing) != opts.Header {
+			return result, fmt.Errorf("refusing to overwrite %s: %w", target, ErrUserFile)
+		}
+	}
+
+	for _, file := range files {
+		target := filepath.Join(dir, file.Name)
+
+		existing, err := os.ReadFile(target)
+		if err == nil && bytes.Equal(existing, file.Content) {
+			result.Unchanged = append(result.Unchanged, target)
+			continue
+		}
+
+		if err := writeAtomic(target, file.Content); err != nil {
+			return result, err
+		}
+		result.Written = append(result.Written, target)
+	}
+
+	if opts.Clean {
+		stale, err := StaleFiles(dir, files, opts.Header)
+		if err != nil {
+			return result, err
+		}
+		for _, path := range stale {
+			if err := os.Remove(path); err != nil {
+				return result, fmt.Errorf("could not remove stale file %s: %w", path, err)
+			}
+			result.Removed = append(result.Removed, path)
+		}
+	}
+
+	return result, nil
+}
+
+// IsGenerated reports whether content carries the generated-code header before its package clause
+func IsGenerated(content []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if generatedHeader.MatchString(line) {
+			return true
+		}
+		if strings.HasPrefix(line, "package ") {
+			return false
+		}
+	}
+	return false
+}
+
+// writeAtomic writes content to a temporary file next to target and renames it into place
+func writeAtomic(target string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return fmt.Errorf("could not create directory for %s: %w", target, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("could not create temporary file: %w", err)
+	}
+	tmpName := tmp.Name()
+
+	// Make sure the temporary file never outlives a failed write
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("could not write to file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("could not write to file: %w", err)
+	}
+	if err := os.Chmod(tmpName, 0o644); err != nil {
+		return fmt.Errorf("could not set file permissions: %w", err)
+	}
+	if err := os.Rename(tmpName, target); err != nil {
+		return fmt.Errorf("could not replace %s: %w", target, err)
+	}
+
+	return nil
+}
+
+// StaleFiles lists the .go files that start with header but are not in files. Only the
+// directories files are written to are searched, not their subdirectories, so generated code
+// of other tools and other packages in the same tree is left alone.
+func StaleFiles(dir string, files []File, header string) ([]string, error) {
+	wanted := make(map[string]bool, len(files))
+	dirs := make(map[string]bool)
+	for _, file := range files {
+		target := filepath.Clean(filepath.Join(dir, file.Name))
+		wanted[target] = true
+		dirs[filepath.Dir(target)] = true
+	}
+
+	var stale []string
+	for searched := range dirs {
+		entries, err := os.ReadDir(searched)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not clean output directory: %w", err)
+		}
+
+		for _, entry := range entries {
+			path := filepath.Join(searched, entry.Name())
+			if entry.IsDir() || filepath.Ext(path) != ".go" || wanted[path] {
+				continue
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("could not clean output directory: %w", err)
+			}
+			if firstLine(content) == header {
+				stale = append(stale, path)
+			}
+		}
+	}
+
+	sort.Strings(stale)
+	return stale, nil
+}
+
+// firstLine returns the first line of content without its line ending
+func firstLine(content []byte) string {
+	line, _, _ := bytes.Cut(content, []byte("\n"))
+	return string(bytes.TrimSuffix(line, []byte("\r")))
+}
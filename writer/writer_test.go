@@ -0,0 +1,203 @@
+package writer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+const generatedHeaderLine = "// Code generated by reddigo-generator. DO NOT EDIT."
+
+const generatedContent = generatedHeaderLine + "\n\npackage reddigo\n"
+
+func TestIsGenerated(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{generatedContent, true},
+		{"// Copyright 2024\n\n// Code generated by stringer. DO NOT EDIT.\n\npackage x\n", true},
+		{"package reddigo\n\n// Code generated by reddigo-generator. DO NOT EDIT.\n", false},
+		{"// Code generated by hand, please edit.\npackage reddigo\n", false},
+		{"package reddigo\n", false},
+		{"<!-- Code generated by reddigo-generator. DO NOT EDIT. -->\n\n# reddigo\n", true},
+	}
+
+	for _, test := range tests {
+		output := IsGenerated([]byte(test.input))
+		if output != test.expected {
+			t.Errorf("For input '%s', expected '%v' but got '%v'", test.input, test.expected, output)
+		}
+	}
+}
+
+func TestWritePreservesUserFiles(t *testing.T) {
+	dir := t.TempDir()
+	userFile := filepath.Join(dir, "custom.go")
+	writeFile(t, userFile, "package reddigo\n\nfunc Custom() {}\n")
+	writeFile(t, filepath.Join(dir, ".git", "HEAD"), "ref: refs/heads/main\n")
+
+	_, err := Write(dir, []File{{Name: "reddigo.go", Content: []byte(generatedContent)}}, Options{Clean: true, Header: generatedHeaderLine})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := readFile(t, userFile); got != "package reddigo\n\nfunc Custom() {}\n" {
+		t.Errorf("user file was modified: %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git", "HEAD")); err != nil {
+		t.Errorf("expected .git to be preserved, got %v", err)
+	}
+	if got := readFile(t, filepath.Join(dir, "reddigo.go")); got != generatedContent {
+		t.Errorf("expected generated file to be written, got %q", got)
+	}
+}
+
+func TestWriteRefusesToOverwriteUserFile(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "reddigo.go")
+	writeFile(t, target, "package reddigo\n")
+
+	_, err := Write(dir, []File{
+		{Name: "other.go", Content: []byte(generatedContent)},
+		{Name: "reddigo.go", Content: []byte(generatedContent)},
+	}, Options{Header: generatedHeaderLine})
+	if !errors.Is(err, ErrUserFile) {
+		t.Fatalf("expected ErrUserFile, got %v", err)
+	}
+
+	if got := readFile(t, target); got != "package reddigo\n" {
+		t.Errorf("user file was modified: %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "other.go")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected no files to be written after a conflict, got %v", err)
+	}
+}
+
+func TestWriteRefusesToOverwriteOtherGenerators(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+	}{
+		{"stringer", "// Code generated by \"stringer -type=Kind\"; DO NOT EDIT.\n\npackage reddigo\n"},
+		{"protoc", "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage reddigo\n"},
+		{"header after a comment", "// Copyright\n" + generatedContent},
+	}
+
+	for _, test := range tests {
+		dir := t.TempDir()
+		target := filepath.Join(dir, "reddigo.go")
+		writeFile(t, target, test.existing)
+
+		_, err := Write(dir, []File{{Name: "reddigo.go", Content: []byte(generatedContent)}}, Options{Header: generatedHeaderLine})
+		if !errors.Is(err, ErrUserFile) {
+			t.Errorf("For input '%s', expected ErrUserFile but got '%v'", test.name, err)
+		}
+		if got := readFile(t, target); got != test.existing {
+			t.Errorf("For input '%s', expected the file to be kept but got '%s'", test.name, got)
+		}
+	}
+
+	if _, err := Write(t.TempDir(), nil, Options{}); err == nil {
+		t.Error("expected an error without a header")
+	}
+}
+
+func TestWriteReplacesGeneratedAndCleansStale(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "reddigo.go"), generatedContent+"\nvar old = 1\n")
+	writeFile(t, filepath.Join(dir, "stale.go"), generatedContent)
+	writeFile(t, filepath.Join(dir, "unchanged.go"), generatedContent)
+
+	files := []File{
+		{Name: "reddigo.go", Content: []byte(generatedContent)},
+		{Name: "unchanged.go", Content: []byte(generatedContent)},
+	}
+
+	result, err := Write(dir, files, Options{Header: generatedHeaderLine})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Written) != 1 || len(result.Unchanged) != 1 || len(result.Removed) != 0 {
+		t.Errorf("unexpected result without clean: %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "stale.go")); err != nil {
+		t.Errorf("expected stale file to survive without clean, got %v", err)
+	}
+
+	result, err = Write(dir, files, Options{Clean: true, Header: generatedHeaderLine})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Removed) != 1 || result.Removed[0] != filepath.Join(dir, "stale.go") {
+		t.Errorf("expected stale.go to be removed, got %+v", result)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) == ".tmp" {
+			t.Errorf("temporary file left behind: %s", entry.Name())
+		}
+	}
+}
+
+func TestStaleFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := []File{
+		{Name: "reddigo.go", Content: []byte(generatedContent)},
+		{Name: filepath.Join("reddigotest", "reddigotest.go"), Content: []byte(generatedContent)},
+	}
+
+	tests := []struct {
+		input    string
+		content  string
+		expected bool
+	}{
+		{"reddigo.go", generatedContent, false},
+		{"stale.go", generatedContent, true},
+		{filepath.Join("reddigotest", "old.go"), generatedContent, true},
+		{"notes.md", generatedContent, false},
+		{"custom.go", "package reddigo\n", false},
+		{"enums_string.go", "// Code generated by stringer. DO NOT EDIT.\n\npackage reddigo\n", false},
+		{"late_header.go", "// Copyright 2024\n" + generatedContent, false},
+		{filepath.Join("internal", "old.go"), generatedContent, false},
+	}
+	for _, test := range tests {
+		writeFile(t, filepath.Join(dir, test.input), test.content)
+	}
+
+	stale, err := StaleFiles(dir, files, generatedHeaderLine)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, test := range tests {
+		output := slices.Contains(stale, filepath.Join(dir, test.input))
+		if output != test.expected {
+			t.Errorf("For input '%s', expected '%v' but got '%v'", test.input, test.expected, output)
+		}
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}